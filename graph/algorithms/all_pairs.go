@@ -0,0 +1,312 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file provides all-pairs shortest paths for a graph, either via
+// repeated breadth-first-search (unweighted graphs) or
+// floyd-warshall (edge-weighted graphs), and some distance based
+// measures derived from them
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/stack"
+	"github.com/anupamk/common-utilz/traversal"
+	"math"
+)
+
+//
+// shortest distance between every pair of vertices in a graph. an
+// unreachable vertex is at an infinite distance
+//
+type AllPairs struct {
+	dist    [][]float64 // dist[v][w]: shortest distance v -> w
+	edge_to [][]int32   // edge_to[v][w]: vertex preceeding 'w' on v -> w
+}
+
+//
+// this function is called to compute the shortest paths between all
+// pairs of vertices in an unweighted graph. each edge counts as a
+// single hop.
+//
+func BFSAllPairs(G graph.GraphOps) (ap *AllPairs) {
+	ap = new_all_pairs(G.V())
+
+	for s := int32(0); s < G.V(); s++ {
+		dist, edge_to := ap.dist[s], ap.edge_to[s]
+
+		bfs_walker := traversal.BFSGraphSubsetWalker(G, s)
+		for E, err := bfs_walker(); err != traversal.EOGS; E, err = bfs_walker() {
+			if E.Src == E.Dst {
+				dist[E.Dst] = 0
+				continue
+			}
+
+			dist[E.Dst] = dist[E.Src] + 1
+			edge_to[E.Dst] = E.Src
+		}
+	}
+
+	return
+}
+
+//
+// this function is called to compute the shortest paths between all
+// pairs of vertices in an edge-weighted graph using the
+// floyd-warshall algorithm. negative edge weights are fine, but an
+// error is signalled if the graph contains a negative cycle.
+//
+func FloydWarshallAllPairs(G graph.WeightedGraphOps) (ap *AllPairs, err error) {
+	ap = new_all_pairs(G.V())
+	dist, edge_to := ap.dist, ap.edge_to
+
+	// direct edges, picking the lightest of parallel edges
+	for v := int32(0); v < G.V(); v++ {
+		dist[v][v] = 0
+
+		weights := G.Weights(v)
+		for i, w := range G.Adj(v) {
+			if weights[i] < dist[v][w] {
+				dist[v][w] = weights[i]
+				edge_to[v][w] = v
+			}
+		}
+	}
+
+	// the canonical floyd-warshall procedure
+	for k := int32(0); k < G.V(); k++ {
+		for i := int32(0); i < G.V(); i++ {
+			if math.IsInf(dist[i][k], 1) {
+				continue
+			}
+
+			for j := int32(0); j < G.V(); j++ {
+				if d := dist[i][k] + dist[k][j]; d < dist[i][j] {
+					dist[i][j] = d
+					edge_to[i][j] = edge_to[k][j]
+				}
+			}
+		}
+	}
+
+	// a vertex with negative distance to itself is on a negative
+	// cycle
+	for v := int32(0); v < G.V(); v++ {
+		if dist[v][v] < 0 {
+			err = fmt.Errorf("error: negative cycle through vertex: %d\n", v)
+			ap = nil
+			return
+		}
+	}
+
+	return
+}
+
+// some commonly used queries on all-pairs shortest paths
+
+//
+// this function returns the shortest distance from v -> w, which is
+// +Inf if 'w' is not reachable from 'v'. signals an error if either
+// v/w are invalid vertices for the graph
+//
+func (ap *AllPairs) Dist(v, w int32) (d float64, err error) {
+	if err = ap.check_vertices(v, w); err != nil {
+		return
+	}
+
+	d = ap.dist[v][w]
+	return
+}
+
+//
+// this function returns true if a path from v -> w exists, false
+// otherwise. signals an error if either v/w are invalid vertices
+//
+func (ap *AllPairs) HasPathTo(v, w int32) (yesno bool, err error) {
+	if err = ap.check_vertices(v, w); err != nil {
+		return
+	}
+
+	yesno = !math.IsInf(ap.dist[v][w], 1)
+	return
+}
+
+//
+// this function enumerates a shortest path from v -> w if such a
+// path exists.
+//
+func (ap *AllPairs) PathTo(v, w int32) (path []int32, err error) {
+	var path_exists bool
+
+	// invalid vertices
+	if path_exists, err = ap.HasPathTo(v, w); err != nil {
+		return
+	}
+
+	// no paths exist
+	if !path_exists {
+		err = fmt.Errorf("no path from: %d to: %d\n", v, w)
+		return
+	}
+
+	// find the path
+	path_stack := stack.New()
+	for x := w; x != v; x = ap.edge_to[v][x] {
+		path_stack.Push(x)
+	}
+	path_stack.Push(v)
+
+	path = make([]int32, path_stack.Len())
+	for i := 0; !path_stack.Empty(); i++ {
+		path[i] = path_stack.Pop().(int32)
+	}
+
+	return
+}
+
+//
+// this function returns the eccentricity of a vertex i.e. distance
+// to the vertex farthest from it. eccentricity is +Inf if some vertex
+// is not reachable from 'v'
+//
+func (ap *AllPairs) Eccentricity(v int32) (ecc float64, err error) {
+	if err = ap.check_vertices(v, v); err != nil {
+		return
+	}
+
+	for _, d := range ap.dist[v] {
+		if d > ecc {
+			ecc = d
+		}
+	}
+
+	return
+}
+
+//
+// diameter of a graph is the maximum eccentricity of any vertex,
+// while radius is the minimum eccentricity of any vertex.
+//
+func (ap *AllPairs) Diameter() (diameter float64) {
+	for _, ecc := range ap.eccentricities() {
+		diameter = math.Max(diameter, ecc)
+	}
+
+	return
+}
+
+func (ap *AllPairs) Radius() (radius float64) {
+	radius = math.Inf(1)
+	for _, ecc := range ap.eccentricities() {
+		radius = math.Min(radius, ecc)
+	}
+
+	return
+}
+
+//
+// center of a graph is the set of vertices whose eccentricity equals
+// the radius, while periphery is the set of vertices whose
+// eccentricity equals the diameter. vertices are returned in
+// ascending order.
+//
+func (ap *AllPairs) Center() []int32    { return ap.vertices_with_eccentricity(ap.Radius()) }
+func (ap *AllPairs) Periphery() []int32 { return ap.vertices_with_eccentricity(ap.Diameter()) }
+
+// regular stuff
+
+func (ap *AllPairs) String() string {
+	str := ""
+
+	for v, dist_v := range ap.dist {
+		str += fmt.Sprintf("%d:", v)
+		for _, d := range dist_v {
+			str += fmt.Sprintf(" %g", d)
+		}
+		str += fmt.Sprintf("\n")
+	}
+
+	return str
+}
+
+//
+// private unexported stuff
+//
+
+// create all-pairs for 'V' vertices, where nothing is reachable
+func new_all_pairs(V int32) (ap *AllPairs) {
+	ap = &AllPairs{
+		dist:    make([][]float64, V),
+		edge_to: make([][]int32, V),
+	}
+
+	for v := int32(0); v < V; v++ {
+		ap.dist[v] = make([]float64, V)
+		ap.edge_to[v] = make([]int32, V)
+
+		for w := range ap.dist[v] {
+			ap.dist[v][w] = math.Inf(1)
+			ap.edge_to[v][w] = -1
+		}
+	}
+
+	return
+}
+
+// signal an error if either v/w are not vertices of the graph
+func (ap *AllPairs) check_vertices(v, w int32) (err error) {
+	V := int32(len(ap.dist))
+
+	if v < 0 || v >= V || w < 0 || w >= V {
+		err = fmt.Errorf("bogus vertex: %d or %d\n", v, w)
+	}
+
+	return
+}
+
+// eccentricity of each vertex of the graph
+func (ap *AllPairs) eccentricities() (ecc []float64) {
+	ecc = make([]float64, len(ap.dist))
+	for v := range ecc {
+		ecc[v], _ = ap.Eccentricity(int32(v))
+	}
+
+	return
+}
+
+// all vertices with a given eccentricity
+func (ap *AllPairs) vertices_with_eccentricity(want float64) (vertex_list []int32) {
+	for v, ecc := range ap.eccentricities() {
+		if ecc == want {
+			vertex_list = append(vertex_list, int32(v))
+		}
+	}
+
+	return
+}
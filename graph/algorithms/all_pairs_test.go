@@ -0,0 +1,179 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file provides test routines for all-pairs shortest paths
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"math"
+	"testing"
+)
+
+// edges of the largest component of graph-001
+var all_pairs_graph_edges = [][2]int32{
+	{0, 5}, {4, 3}, {0, 1}, {6, 4},
+	{5, 4}, {0, 2}, {0, 6}, {5, 3},
+}
+
+// a small edge-weighted digraph
+var all_pairs_ewd_edges = []struct {
+	v, w   int32
+	weight float64
+}{
+	{4, 5, 0.35}, {5, 4, 0.35}, {4, 7, 0.37}, {5, 7, 0.28},
+	{7, 5, 0.28}, {5, 1, 0.32}, {0, 4, 0.38}, {0, 2, 0.26},
+	{7, 3, 0.39}, {1, 3, 0.29}, {2, 7, 0.34}, {6, 2, 0.40},
+	{3, 6, 0.52}, {6, 0, 0.58}, {6, 4, 0.93},
+}
+
+// create an undirected graph with 'V' vertices from a list of edges
+func create_test_graph(V int32, edges [][2]int32) *graph.Graph {
+	g := graph.New(V)
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1])
+	}
+
+	return g
+}
+
+// create the edge-weighted test digraph
+func create_test_ewd() *graph.WeightedDigraph {
+	g := graph.CreateWeightedDigraph(8)
+	for _, e := range all_pairs_ewd_edges {
+		g.AddEdge(e.v, e.w, e.weight)
+	}
+
+	return g
+}
+
+func ExampleBFSAllPairs() {
+	g := create_test_graph(7, all_pairs_graph_edges)
+	ap := BFSAllPairs(g)
+
+	path, _ := ap.PathTo(1, 3)
+	fmt.Println(path)
+	fmt.Println(ap.Diameter(), ap.Radius())
+	fmt.Println(ap.Center(), ap.Periphery())
+
+	// Output:
+	// [1 0 5 3]
+	// 3 2
+	// [0 5 6] [1 2 3 4]
+}
+
+func ExampleFloydWarshallAllPairs() {
+	g := create_test_ewd()
+	ap, _ := FloydWarshallAllPairs(g)
+
+	for w := int32(0); w < g.V(); w++ {
+		d, _ := ap.Dist(0, w)
+		path, _ := ap.PathTo(0, w)
+		fmt.Printf("%d: %.2f %v\n", w, d, path)
+	}
+
+	// Output:
+	// 0: 0.00 [0]
+	// 1: 1.05 [0 4 5 1]
+	// 2: 0.26 [0 2]
+	// 3: 0.99 [0 2 7 3]
+	// 4: 0.38 [0 4]
+	// 5: 0.73 [0 4 5]
+	// 6: 1.51 [0 2 7 3 6]
+	// 7: 0.60 [0 2 7]
+}
+
+//
+// with unit weights, floyd-warshall must agree with repeated bfs on
+// every pair of vertices
+//
+func TestAllPairsBFSMatchesFloydWarshall(t *testing.T) {
+	g := create_test_graph(7, all_pairs_graph_edges)
+	wg := graph.NewWeighted(g.V())
+	for _, e := range all_pairs_graph_edges {
+		wg.AddEdge(e[0], e[1], 1)
+	}
+
+	bfs_ap := BFSAllPairs(g)
+	fw_ap, err := FloydWarshallAllPairs(wg)
+	if err != nil {
+		t.Logf("failed: unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	for v := int32(0); v < g.V(); v++ {
+		for w := int32(0); w < g.V(); w++ {
+			d1, _ := bfs_ap.Dist(v, w)
+			d2, _ := fw_ap.Dist(v, w)
+
+			if d1 != d2 {
+				t.Logf("failed: %d->%d, bfs-dist: %g, fw-dist: %g\n", v, w, d1, d2)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestAllPairsUnreachable(t *testing.T) {
+	g := create_test_graph(4, [][2]int32{{0, 1}, {2, 3}})
+	ap := BFSAllPairs(g)
+
+	if yes, _ := ap.HasPathTo(0, 3); yes {
+		t.Logf("failed: expected no path 0->3\n")
+		t.Fail()
+	}
+
+	if _, err := ap.PathTo(0, 3); err == nil {
+		t.Logf("failed: expected error for path 0->3\n")
+		t.Fail()
+	}
+
+	if d := ap.Diameter(); !math.IsInf(d, 1) {
+		t.Logf("failed: expected infinite diameter, got: %g\n", d)
+		t.Fail()
+	}
+
+	if _, err := ap.Dist(0, 4); err == nil {
+		t.Logf("failed: expected error for bogus vertex 4\n")
+		t.Fail()
+	}
+}
+
+func TestFloydWarshallNegativeCycle(t *testing.T) {
+	g := graph.CreateWeightedDigraph(3)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, -2)
+	g.AddEdge(2, 0, 0.5)
+
+	if _, err := FloydWarshallAllPairs(g); err == nil {
+		t.Logf("failed: expected negative cycle error\n")
+		t.Fail()
+	}
+}
@@ -0,0 +1,153 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file implements a* search, which finds the shortest path
+// between a pair of vertices in an edge-weighted graph, guided by a
+// user supplied heuristic
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/stack"
+	"math"
+)
+
+//
+// estimate of the remaining cost from a vertex to the target. for the
+// search to return shortest paths, the estimate must never exceed the
+// actual remaining cost.
+//
+type Heuristic func(v int32) float64
+
+//
+// result of an a* search from source -> target
+//
+type AStarPath struct {
+	path     []int32
+	cost     float64
+	expanded int32
+}
+
+//
+// this function is called to compute the shortest path from source
+// -> target in an edge-weighted graph using a* search. a nil
+// heuristic degenerates to dijkstra's algorithm.
+//
+// edge weights must be non-negative, an error is signalled
+// otherwise. an error is also signalled if the target isn't reachable
+// from the source.
+//
+func AStarSearch(G graph.WeightedGraphOps, source, target int32, h Heuristic) (sp *AStarPath, err error) {
+	if source < 0 || source >= G.V() || target < 0 || target >= G.V() {
+		err = fmt.Errorf("bogus vertex: %d or %d\n", source, target)
+		return
+	}
+
+	if h == nil {
+		h = func(int32) float64 { return 0 }
+	}
+
+	sp = &AStarPath{}
+	dist_to := make([]float64, G.V())
+	edge_to := make([]int32, G.V())
+
+	for v := range dist_to {
+		dist_to[v] = math.Inf(1)
+	}
+	dist_to[source] = 0
+
+	open_set := new_vertex_dist_heap()
+	open_set.push(vertex_dist_t{source, h(source), 0})
+
+	for !open_set.empty() {
+		next := open_set.pop()
+		v := next.v
+
+		// a shorter route to 'v' was found since this was queued
+		if next.dist > dist_to[v] {
+			continue
+		}
+
+		sp.expanded += 1
+		if v == target {
+			break
+		}
+
+		weights := G.Weights(v)
+		for i, w := range G.Adj(v) {
+			if weights[i] < 0 {
+				err = fmt.Errorf("error: negative weight edge: %d->%d\n", v, w)
+				sp = nil
+				return
+			}
+
+			if d := dist_to[v] + weights[i]; d < dist_to[w] {
+				dist_to[w] = d
+				edge_to[w] = v
+				open_set.push(vertex_dist_t{w, d + h(w), d})
+			}
+		}
+	}
+
+	if math.IsInf(dist_to[target], 1) {
+		err = fmt.Errorf("no path from: %d to: %d\n", source, target)
+		sp = nil
+		return
+	}
+
+	// find the path
+	path_stack := stack.New()
+	for v := target; v != source; v = edge_to[v] {
+		path_stack.Push(v)
+	}
+	path_stack.Push(source)
+
+	sp.path = make([]int32, path_stack.Len())
+	for i := 0; !path_stack.Empty(); i++ {
+		sp.path[i] = path_stack.Pop().(int32)
+	}
+	sp.cost = dist_to[target]
+
+	return
+}
+
+//
+// the path source -> target, its cost, and number of vertices that
+// were expanded to find it.
+//
+func (sp *AStarPath) Path() []int32   { return sp.path }
+func (sp *AStarPath) Cost() float64   { return sp.cost }
+func (sp *AStarPath) Expanded() int32 { return sp.expanded }
+
+// regular stuff
+
+func (sp *AStarPath) String() string {
+	return fmt.Sprintf("path: %v, cost: %g, expanded: %d", sp.path, sp.cost, sp.expanded)
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file provides test routines for a* search
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"math"
+	"testing"
+)
+
+//
+// create a rows x cols grid with unit weight edges between adjacent
+// cells. cells in 'walls' are left disconnected.
+//
+func create_test_grid(rows, cols int32, walls map[int32]bool) *graph.WeightedGraph {
+	g := graph.NewWeighted(rows * cols)
+
+	for r := int32(0); r < rows; r++ {
+		for c := int32(0); c < cols; c++ {
+			v := r*cols + c
+			if walls[v] {
+				continue
+			}
+
+			if c+1 < cols && !walls[v+1] {
+				g.AddEdge(v, v+1, 1)
+			}
+			if r+1 < rows && !walls[v+cols] {
+				g.AddEdge(v, v+cols, 1)
+			}
+		}
+	}
+
+	return g
+}
+
+// manhattan distance to 'target' on a grid with 'cols' columns
+func manhattan_heuristic(target, cols int32) Heuristic {
+	return func(v int32) float64 {
+		dr := math.Abs(float64(v/cols - target/cols))
+		dc := math.Abs(float64(v%cols - target%cols))
+		return dr + dc
+	}
+}
+
+func ExampleAStarSearch() {
+	g := create_test_ewd()
+
+	sp, _ := AStarSearch(g, 0, 6, nil)
+	fmt.Printf("%v %.2f\n", sp.Path(), sp.Cost())
+
+	// Output:
+	// [0 2 7 3 6] 1.51
+}
+
+//
+// on a grid, a* with manhattan distance must find a path just as
+// short as the one found without a heuristic, while expanding fewer
+// vertices, and no more than bfs visits.
+//
+func TestAStarGrid(t *testing.T) {
+	rows, cols := int32(16), int32(16)
+	walls := map[int32]bool{}
+	for r := int32(0); r < rows-2; r++ {
+		walls[r*cols+cols/2] = true
+	}
+
+	g := create_test_grid(rows, cols, walls)
+	source, target := int32(0), rows*cols-1
+
+	guided, err := AStarSearch(g, source, target, manhattan_heuristic(target, cols))
+	if err != nil {
+		t.Logf("failed: unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	blind, _ := AStarSearch(g, source, target, nil)
+	if guided.Cost() != blind.Cost() {
+		t.Logf("failed: guided-cost: %g, blind-cost: %g\n", guided.Cost(), blind.Cost())
+		t.Fail()
+	}
+
+	bfs_path, _ := BFSPath(g, source).PathTo(target)
+	if guided.Cost() != float64(len(bfs_path)-1) {
+		t.Logf("failed: guided-cost: %g, bfs-hops: %d\n", guided.Cost(), len(bfs_path)-1)
+		t.Fail()
+	}
+
+	if guided.Expanded() >= blind.Expanded() {
+		t.Logf("failed: guided-expanded: %d, blind-expanded: %d\n", guided.Expanded(), blind.Expanded())
+		t.Fail()
+	}
+
+	if int32(len(guided.Path())) != int32(guided.Cost())+1 {
+		t.Logf("failed: path: %v, cost: %g\n", guided.Path(), guided.Cost())
+		t.Fail()
+	}
+}
+
+func TestAStarErrors(t *testing.T) {
+	g := create_test_grid(2, 2, map[int32]bool{1: true, 2: true})
+
+	if _, err := AStarSearch(g, 0, 3, nil); err == nil {
+		t.Logf("failed: expected no path from 0 to 3\n")
+		t.Fail()
+	}
+
+	if _, err := AStarSearch(g, 0, 4, nil); err == nil {
+		t.Logf("failed: expected error for bogus vertex 4\n")
+		t.Fail()
+	}
+
+	ng := graph.CreateWeightedDigraph(2)
+	ng.AddEdge(0, 1, -1)
+	if _, err := AStarSearch(ng, 0, 1, nil); err == nil {
+		t.Logf("failed: expected error for negative weight\n")
+		t.Fail()
+	}
+}
@@ -91,6 +91,12 @@ func (CC *ConnectedComponent) IsConnected(v, w int32) (yesno bool, err error) {
 //
 func (CC *ConnectedComponent) Count() int32 { return CC.count }
 
+//
+// returns the id of the component containing vertex 'v'. ids are in
+// the range {1, Count()}
+//
+func (CC *ConnectedComponent) Id(v int32) int32 { return CC.id[v] }
+
 // regular stuff
 
 func (CC *ConnectedComponent) String() string {
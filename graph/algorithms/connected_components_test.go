@@ -32,6 +32,8 @@ package algorithms
 import (
 	"fmt"
 	"github.com/anupamk/common-utilz/graph"
+	"io/ioutil"
+	"os"
 	"testing"
 )
 
@@ -89,6 +91,57 @@ func TestCCIsConnected(t *testing.T) {
 	}
 }
 
+func TestCCId(t *testing.T) {
+	g := create_test_graph(5, [][2]int32{{0, 1}, {2, 3}, {3, 4}})
+	cc := New(g)
+
+	want := []int32{1, 1, 2, 2, 2}
+	for v := int32(0); v < g.V(); v++ {
+		if got := cc.Id(v); got != want[v] {
+			t.Logf("failed: vertex: %d, expected-id: %d, got: %d\n", v, want[v], got)
+			t.Fail()
+		}
+	}
+}
+
+//
+// components of a memory-mapped graph must match those of the graph
+// it was written from
+//
+func TestCCMappedGraph(t *testing.T) {
+	g, _ := graph.LoadFromFile("../data/graph-004.data")
+
+	f, err := ioutil.TempFile("", "cc-mapped")
+	if err != nil {
+		t.Logf("failed: unexpected error: %s\n", err)
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+
+	g.WriteBinary(f, false)
+	f.Close()
+
+	m, err := graph.OpenMapped(f.Name(), true)
+	if err != nil {
+		t.Logf("failed: unexpected error: %s\n", err)
+		t.FailNow()
+	}
+	defer m.Close()
+
+	cc, mcc := New(g), New(m)
+	if cc.Count() != mcc.Count() {
+		t.Logf("failed: expected-count: %d, got: %d\n", cc.Count(), mcc.Count())
+		t.FailNow()
+	}
+
+	for v := int32(0); v < g.V(); v++ {
+		if cc.Id(v) != mcc.Id(v) {
+			t.Logf("failed: vertex: %d, expected-id: %d, got: %d\n", v, cc.Id(v), mcc.Id(v))
+			t.Fail()
+		}
+	}
+}
+
 func BenchmarkConnectedComponents(bench *testing.B) {
 	fname := "../data/graph-004.data"
 	g, _ := graph.LoadFromFile(fname)
@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file implements kahn's algorithm for computing the
+// topological-sort order of a digraph. unlike
+// ComputeTopologicalOrder(...), cycles are detected as part of the
+// same pass, and ties can be broken deterministically.
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/queue"
+	"sort"
+)
+
+//
+// this function computes the topological order for a given digraph
+// using kahn's algorithm. when 'lowest_id_first' is true, amongst all
+// the vertices that are ready to be emitted, the one with lowest id
+// is always chosen first, thus making the ordering independent of
+// the order in which edges were added to the digraph. otherwise,
+// vertices are emitted in the order in which they became ready.
+//
+// returns an error if no such ordering is possible
+//
+func KahnTopologicalOrder(G graph.GraphOps, lowest_id_first bool) (ordering []int32, err error) {
+	var push func(int32)
+	var pop func() int32
+	var empty func() bool
+
+	in_degree := compute_in_degree(G)
+
+	// select the vertex-frontier as per tie-breaking policy
+	if lowest_id_first {
+		vh := new_vertex_heap()
+		push, pop, empty = vh.push, vh.pop, vh.empty
+	} else {
+		vq := queue.New()
+		push = func(v int32) { vq.Push(v) }
+		pop = func() int32 { return vq.Pop().(int32) }
+		empty = vq.Empty
+	}
+
+	// sources for starters
+	for v := int32(0); v < G.V(); v++ {
+		if in_degree[v] == 0 {
+			push(v)
+		}
+	}
+
+	ordering = make([]int32, 0, G.V())
+	for !empty() {
+		v := pop()
+		ordering = append(ordering, v)
+
+		for _, w := range G.Adj(v) {
+			if in_degree[w] -= 1; in_degree[w] == 0 {
+				push(w)
+			}
+		}
+	}
+
+	if int32(len(ordering)) != G.V() {
+		err = kahn_cycle_error(in_degree)
+		ordering = nil
+	}
+
+	return
+}
+
+//
+// this function computes the topological order for a given digraph
+// grouped into levels. all vertices in a level depend only on the
+// vertices in earlier levels, and thus, can be processed in
+// parallel. vertices within a level are in ascending order of their
+// ids.
+//
+// returns an error if no such ordering is possible
+//
+func KahnTopologicalLevels(G graph.GraphOps) (levels [][]int32, err error) {
+	var current []int32
+	var seen int32
+
+	in_degree := compute_in_degree(G)
+
+	// sources make up the first level
+	for v := int32(0); v < G.V(); v++ {
+		if in_degree[v] == 0 {
+			current = append(current, v)
+		}
+	}
+
+	for len(current) > 0 {
+		var next []int32
+
+		levels = append(levels, current)
+		seen += int32(len(current))
+
+		for _, v := range current {
+			for _, w := range G.Adj(v) {
+				if in_degree[w] -= 1; in_degree[w] == 0 {
+					next = append(next, w)
+				}
+			}
+		}
+
+		sort.Sort(vertex_heap_t(next))
+		current = next
+	}
+
+	if seen != G.V() {
+		err = kahn_cycle_error(in_degree)
+		levels = nil
+	}
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// in-degree of each vertex in a digraph
+func compute_in_degree(G graph.GraphOps) (in_degree []int32) {
+	in_degree = make([]int32, G.V())
+
+	for v := int32(0); v < G.V(); v++ {
+		for _, w := range G.Adj(v) {
+			in_degree[w] += 1
+		}
+	}
+
+	return
+}
+
+//
+// once kahn's algorithm runs out of vertices to emit, every vertex
+// with a non-zero in-degree either lies on a cycle, or is reachable
+// from one. report them all.
+//
+func kahn_cycle_error(in_degree []int32) error {
+	var stuck []int32
+
+	for v, d := range in_degree {
+		if d > 0 {
+			stuck = append(stuck, int32(v))
+		}
+	}
+
+	return fmt.Errorf("error: digraph has a cycle, unordered vertices: %v. no ordering possible\n", stuck)
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file tests kahn's topological-sort implementation
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"testing"
+)
+
+// edges of a small dag
+var kahn_dag_edges = [][2]int32{
+	{2, 3}, {0, 6}, {0, 1}, {2, 0}, {11, 12},
+	{9, 12}, {9, 10}, {9, 11}, {3, 5}, {8, 7},
+	{5, 4}, {0, 5}, {6, 4}, {6, 9}, {7, 6},
+}
+
+// create a digraph with 'V' vertices from a list of edges
+func create_test_digraph(V int32, edges [][2]int32) *graph.Digraph {
+	g := graph.CreateDigraph(V)
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1])
+	}
+
+	return g
+}
+
+func ExampleKahnTopologicalOrder() {
+	g := create_test_digraph(13, kahn_dag_edges)
+
+	order, _ := KahnTopologicalOrder(g, true)
+	fmt.Println(order)
+
+	// Output:
+	// [2 0 1 3 5 8 7 6 4 9 10 11 12]
+}
+
+func ExampleKahnTopologicalLevels() {
+	g := create_test_digraph(13, kahn_dag_edges)
+
+	levels, _ := KahnTopologicalLevels(g)
+	for i, l := range levels {
+		fmt.Printf("%d: %v\n", i, l)
+	}
+
+	// Output:
+	// 0: [2 8]
+	// 1: [0 3 7]
+	// 2: [1 5 6]
+	// 3: [4 9]
+	// 4: [10 11]
+	// 5: [12]
+}
+
+//
+// every edge v->w must have 'v' appear before 'w' in the ordering,
+// irrespective of the tie-breaking policy
+//
+func TestKahnOrderIsTopological(t *testing.T) {
+	g := create_test_digraph(13, kahn_dag_edges)
+
+	for _, lowest_id_first := range []bool{false, true} {
+		order, err := KahnTopologicalOrder(g, lowest_id_first)
+		if err != nil {
+			t.Logf("failed: unexpected error: %s\n", err)
+			t.Fail()
+			continue
+		}
+
+		position := make([]int, g.V())
+		for i, v := range order {
+			position[v] = i
+		}
+
+		for v := int32(0); v < g.V(); v++ {
+			for _, w := range g.Adj(v) {
+				if position[v] > position[w] {
+					t.Logf("failed: edge %d->%d violated, order: %v\n", v, w, order)
+					t.Fail()
+				}
+			}
+		}
+	}
+}
+
+//
+// lowest-id tie breaking must produce the same ordering, no matter
+// which order the edges are added in.
+//
+func TestKahnOrderIsDeterministic(t *testing.T) {
+	reversed := make([][2]int32, len(kahn_dag_edges))
+	for i, e := range kahn_dag_edges {
+		reversed[len(kahn_dag_edges)-1-i] = e
+	}
+
+	o1, _ := KahnTopologicalOrder(create_test_digraph(13, kahn_dag_edges), true)
+	o2, _ := KahnTopologicalOrder(create_test_digraph(13, reversed), true)
+
+	if fmt.Sprint(o1) != fmt.Sprint(o2) {
+		t.Logf("failed: order-1: %v, order-2: %v\n", o1, o2)
+		t.Fail()
+	}
+}
+
+func TestKahnDetectsCycle(t *testing.T) {
+	g := create_test_digraph(5, [][2]int32{{0, 1}, {1, 2}, {2, 3}, {3, 1}, {3, 4}})
+
+	if order, err := KahnTopologicalOrder(g, true); err == nil {
+		t.Logf("failed: expected cycle, got order: %v\n", order)
+		t.Fail()
+	}
+
+	if levels, err := KahnTopologicalLevels(g); err == nil {
+		t.Logf("failed: expected cycle, got levels: %v\n", levels)
+		t.Fail()
+	}
+}
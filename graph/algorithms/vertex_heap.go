@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file implements min-heaps of vertices, used wherever the
+// lowest-numbered (or the nearest) vertex needs to be picked first
+//
+package algorithms
+
+import (
+	"container/heap"
+)
+
+// []int32 heap interface
+type vertex_heap_t []int32
+
+func (h vertex_heap_t) Len() int            { return len(h) }
+func (h vertex_heap_t) Less(i, j int) bool  { return h[i] < h[j] }
+func (h vertex_heap_t) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *vertex_heap_t) Push(x interface{}) { *h = append(*h, x.(int32)) }
+
+func (h *vertex_heap_t) Pop() (x interface{}) {
+	old := *h
+	n := len(old)
+
+	x = old[n-1]
+	*h = old[:n-1]
+
+	return
+}
+
+// create a new (empty) vertex heap
+func new_vertex_heap() *vertex_heap_t {
+	vh := make(vertex_heap_t, 0)
+	return &vh
+}
+
+func (h *vertex_heap_t) push(v int32) { heap.Push(h, v) }
+func (h *vertex_heap_t) pop() int32   { return heap.Pop(h).(int32) }
+func (h *vertex_heap_t) empty() bool  { return len(*h) == 0 }
+
+//
+// a vertex along with its priority, and its distance from the source
+// at the time it was queued. entries are never updated in place,
+// instead a fresh entry is pushed, and stale ones are skipped at pop
+// time
+//
+type vertex_dist_t struct {
+	v        int32
+	priority float64
+	dist     float64
+}
+
+// []vertex_dist_t heap interface
+type vertex_dist_heap_t []vertex_dist_t
+
+func (h vertex_dist_heap_t) Len() int            { return len(h) }
+func (h vertex_dist_heap_t) Less(i, j int) bool  { return h[i].priority < h[j].priority }
+func (h vertex_dist_heap_t) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *vertex_dist_heap_t) Push(x interface{}) { *h = append(*h, x.(vertex_dist_t)) }
+
+func (h *vertex_dist_heap_t) Pop() (x interface{}) {
+	old := *h
+	n := len(old)
+
+	x = old[n-1]
+	*h = old[:n-1]
+
+	return
+}
+
+// create a new (empty) vertex-distance heap
+func new_vertex_dist_heap() *vertex_dist_heap_t {
+	vh := make(vertex_dist_heap_t, 0)
+	return &vh
+}
+
+func (h *vertex_dist_heap_t) push(vd vertex_dist_t) { heap.Push(h, vd) }
+func (h *vertex_dist_heap_t) pop() vertex_dist_t    { return heap.Pop(h).(vertex_dist_t) }
+func (h *vertex_dist_heap_t) empty() bool           { return len(*h) == 0 }
@@ -0,0 +1,299 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file implements yen's algorithm for computing the k shortest
+// loopless paths between a pair of vertices
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"math"
+	"sort"
+)
+
+//
+// this function returns upto 'k' shortest loopless paths from source
+// -> target in an unweighted graph, where path length is the number
+// of hops. paths are ordered by their length, and paths of same
+// length in lexicographic order of their vertices.
+//
+// fewer than 'k' paths are returned if that is all there is. an error
+// is signalled if no path exists at all.
+//
+func YenKShortestPaths(G graph.GraphOps, source, target int32, k int32) (paths [][]int32, err error) {
+	unit_weight := func(v int32, i int) float64 { return 1 }
+
+	paths, _, err = yen_k_shortest_paths(G, unit_weight, source, target, k)
+	return
+}
+
+//
+// this function returns upto 'k' shortest loopless paths from source
+// -> target in an edge-weighted graph, along with the cost of each
+// path. edge weights must be non-negative, an error is signalled
+// otherwise.
+//
+// paths are ordered by their cost, and paths of same cost in
+// lexicographic order of their vertices.
+//
+func YenKShortestWeightedPaths(G graph.WeightedGraphOps, source, target int32, k int32) (paths [][]int32, costs []float64, err error) {
+	for v := int32(0); v < G.V(); v++ {
+		for i, weight := range G.Weights(v) {
+			if weight < 0 {
+				err = fmt.Errorf("error: negative weight edge: %d->%d\n", v, G.Adj(v)[i])
+				return
+			}
+		}
+	}
+
+	edge_weight := func(v int32, i int) float64 { return G.Weights(v)[i] }
+
+	return yen_k_shortest_paths(G, edge_weight, source, target, k)
+}
+
+//
+// private unexported stuff
+//
+
+// weight of the i'th edge out of 'v'
+type edge_weight_fn func(v int32, i int) float64
+
+// a path along with its cost
+type costed_path_t struct {
+	path []int32
+	cost float64
+}
+
+// ordering of costed paths, by cost and then by vertices
+type costed_path_list_t []costed_path_t
+
+func (c costed_path_list_t) Len() int      { return len(c) }
+func (c costed_path_list_t) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+
+func (c costed_path_list_t) Less(i, j int) bool {
+	if c[i].cost != c[j].cost {
+		return c[i].cost < c[j].cost
+	}
+
+	x, y := c[i].path, c[j].path
+	for m := 0; m < len(x) && m < len(y); m++ {
+		if x[m] != y[m] {
+			return x[m] < y[m]
+		}
+	}
+
+	return len(x) < len(y)
+}
+
+//
+// the canonical yen's procedure. each of the k-1 subsequent paths is
+// found by deviating from the previous one at each of its vertices
+// (the spur vertex), while forbidding edges already taken by known
+// paths with the same prefix (the root path), and forbidding
+// vertices on the root path itself so that paths stay loopless.
+//
+func yen_k_shortest_paths(G graph.GraphOps, weight edge_weight_fn, source, target int32, k int32) (paths [][]int32, costs []float64, err error) {
+	var found, candidates costed_path_list_t
+
+	if source < 0 || source >= G.V() || target < 0 || target >= G.V() {
+		err = fmt.Errorf("bogus vertex: %d or %d\n", source, target)
+		return
+	}
+
+	if k <= 0 {
+		err = fmt.Errorf("bogus path count: %d\n", k)
+		return
+	}
+
+	removed_vertex := make([]bool, G.V())
+	removed_edge := make(map[[2]int32]bool)
+
+	first, ok := restricted_dijkstra(G, weight, source, target, removed_vertex, removed_edge)
+	if !ok {
+		err = fmt.Errorf("no path from: %d to: %d\n", source, target)
+		return
+	}
+	found = append(found, first)
+
+	for int32(len(found)) < k {
+		prev := found[len(found)-1].path
+
+		for i := 0; i < len(prev)-1; i++ {
+			spur, root := prev[i], prev[:i+1]
+
+			// edges leaving the root path along known paths
+			for _, p := range found {
+				if len(p.path) > i+1 && cmp_prefix(p.path, root) {
+					removed_edge[[2]int32{p.path[i], p.path[i+1]}] = true
+				}
+			}
+
+			// root path vertices, except the spur vertex
+			for _, v := range root[:i] {
+				removed_vertex[v] = true
+			}
+
+			spur_path, ok := restricted_dijkstra(G, weight, spur, target, removed_vertex, removed_edge)
+			if ok {
+				total := costed_path_t{
+					path: append(append([]int32(nil), root[:i]...), spur_path.path...),
+					cost: path_cost(G, weight, root) + spur_path.cost,
+				}
+
+				if !contains_path(found, total.path) && !contains_path(candidates, total.path) {
+					candidates = append(candidates, total)
+				}
+			}
+
+			// restore the graph for the next spur vertex
+			for _, v := range root[:i] {
+				removed_vertex[v] = false
+			}
+			for e := range removed_edge {
+				delete(removed_edge, e)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		// cheapest candidate is the next shortest path
+		sort.Sort(candidates)
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	sort.Stable(found)
+
+	paths = make([][]int32, len(found))
+	costs = make([]float64, len(found))
+	for i, p := range found {
+		paths[i], costs[i] = p.path, p.cost
+	}
+
+	return
+}
+
+//
+// dijkstra's shortest path from source -> target, ignoring removed
+// vertices and edges. returns false if target isn't reachable.
+//
+func restricted_dijkstra(G graph.GraphOps, weight edge_weight_fn, source, target int32, removed_vertex []bool, removed_edge map[[2]int32]bool) (sp costed_path_t, ok bool) {
+	dist_to := make([]float64, G.V())
+	edge_to := make([]int32, G.V())
+
+	for v := range dist_to {
+		dist_to[v] = math.Inf(1)
+	}
+	dist_to[source] = 0
+
+	pq := new_vertex_dist_heap()
+	pq.push(vertex_dist_t{source, 0, 0})
+
+	for !pq.empty() {
+		next := pq.pop()
+		v := next.v
+
+		if next.dist > dist_to[v] {
+			continue
+		}
+		if v == target {
+			break
+		}
+
+		for i, w := range G.Adj(v) {
+			if removed_vertex[w] || removed_edge[[2]int32{v, w}] {
+				continue
+			}
+
+			if d := dist_to[v] + weight(v, i); d < dist_to[w] {
+				dist_to[w] = d
+				edge_to[w] = v
+				pq.push(vertex_dist_t{w, d, d})
+			}
+		}
+	}
+
+	if math.IsInf(dist_to[target], 1) {
+		return
+	}
+
+	for v := target; v != source; v = edge_to[v] {
+		sp.path = append(sp.path, v)
+	}
+	sp.path = append(sp.path, source)
+
+	for m, n := 0, len(sp.path)-1; m < n; m, n = m+1, n-1 {
+		sp.path[m], sp.path[n] = sp.path[n], sp.path[m]
+	}
+
+	sp.cost, ok = dist_to[target], true
+	return
+}
+
+// cost of a path, taking the lightest of any parallel edges
+func path_cost(G graph.GraphOps, weight edge_weight_fn, path []int32) (cost float64) {
+	for m := 1; m < len(path); m++ {
+		v, w := path[m-1], path[m]
+		lightest := math.Inf(1)
+
+		for i, x := range G.Adj(v) {
+			if x == w {
+				lightest = math.Min(lightest, weight(v, i))
+			}
+		}
+		cost += lightest
+	}
+
+	return
+}
+
+// returns true if 'prefix' is a prefix of 'path'
+func cmp_prefix(path, prefix []int32) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+
+	head := path[:len(prefix)]
+	return slice_utils.CmpInt32Slice(&head, &prefix)
+}
+
+// returns true if 'path' is one of the paths in the list
+func contains_path(list costed_path_list_t, path []int32) bool {
+	for _, p := range list {
+		if slice_utils.CmpInt32Slice(&p.path, &path) {
+			return true
+		}
+	}
+
+	return false
+}
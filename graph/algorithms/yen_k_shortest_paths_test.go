@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// couple of commonly used algorithms for graphs are provided in this
+// package.
+//
+// this file provides test routines for yen's k shortest paths
+//
+package algorithms
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"testing"
+)
+
+func ExampleYenKShortestPaths() {
+	g := create_test_graph(6, [][2]int32{
+		{0, 5}, {2, 4}, {2, 3}, {1, 2},
+		{0, 1}, {3, 4}, {3, 5}, {0, 2},
+	})
+
+	paths, _ := YenKShortestPaths(g, 0, 3, 4)
+	for _, p := range paths {
+		fmt.Println(p)
+	}
+
+	// Output:
+	// [0 2 3]
+	// [0 5 3]
+	// [0 1 2 3]
+	// [0 2 4 3]
+}
+
+func ExampleYenKShortestWeightedPaths() {
+	// vertices c, d, e, f, g, h are 0 .. 5
+	g := graph.CreateWeightedDigraph(6)
+	for _, e := range []struct {
+		v, w   int32
+		weight float64
+	}{
+		{0, 1, 3}, {0, 2, 2}, {1, 3, 4}, {2, 1, 1}, {2, 3, 2},
+		{2, 4, 3}, {3, 4, 2}, {3, 5, 1}, {4, 5, 2},
+	} {
+		g.AddEdge(e.v, e.w, e.weight)
+	}
+
+	paths, costs, _ := YenKShortestWeightedPaths(g, 0, 5, 3)
+	for i, p := range paths {
+		fmt.Println(p, costs[i])
+	}
+
+	// Output:
+	// [0 2 3 5] 5
+	// [0 2 4 5] 7
+	// [0 1 3 5] 8
+}
+
+//
+// asking for more paths than there are, must return all of them,
+// each one loopless, and in non-decreasing order of cost
+//
+func TestYenAllPaths(t *testing.T) {
+	g := create_test_graph(7, all_pairs_graph_edges)
+
+	paths, err := YenKShortestPaths(g, 1, 3, 100)
+	if err != nil {
+		t.Logf("failed: unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	// 1-0, and then to 3 via {5}, {6 4}, {5 4}, {6 4 5}
+	if len(paths) != 4 {
+		t.Logf("failed: expected 4 paths, got: %v\n", paths)
+		t.Fail()
+	}
+
+	for i, p := range paths {
+		seen := make(map[int32]bool)
+		for _, v := range p {
+			if seen[v] {
+				t.Logf("failed: path with loop: %v\n", p)
+				t.Fail()
+			}
+			seen[v] = true
+		}
+
+		if i > 0 && len(p) < len(paths[i-1]) {
+			t.Logf("failed: paths out of order: %v\n", paths)
+			t.Fail()
+		}
+	}
+}
+
+func TestYenErrors(t *testing.T) {
+	g := create_test_graph(4, [][2]int32{{0, 1}, {2, 3}})
+
+	if _, err := YenKShortestPaths(g, 0, 3, 2); err == nil {
+		t.Logf("failed: expected no path from 0 to 3\n")
+		t.Fail()
+	}
+
+	if _, err := YenKShortestPaths(g, 0, 1, 0); err == nil {
+		t.Logf("failed: expected error for k = 0\n")
+		t.Fail()
+	}
+
+	ng := graph.CreateWeightedDigraph(2)
+	ng.AddEdge(0, 1, -1)
+	if _, _, err := YenKShortestWeightedPaths(ng, 0, 1, 1); err == nil {
+		t.Logf("failed: expected error for negative weight\n")
+		t.Fail()
+	}
+}
@@ -107,7 +107,8 @@ func main() {
 		log.Printf("-- symbol-graph created. vertices: '%d', edges: '%d' --\n", sg_g.V(), sg_g.E())
 	}
 
-	if !sg.Contains(source) {
+	src_vertex, ok := sg.Lookup(source)
+	if !ok {
 		fmt.Fprintf(os.Stderr, "fatal-error: '%s' not found in data-base\n", source)
 		flag.Usage()
 
@@ -116,18 +117,8 @@ func main() {
 
 	//
 	// ok, so by now, we have a valid graph, and a valid source,
-	// let's answer some questions...
+	// let's answer some questions in a query-rsp loop...
 	//
-
-	// determine all paths from source -> other vertices on the
-	// graph
-	all_paths, err := traversal.SingleSourceShortestPaths(sg.G(), sg.Index(source))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "fatal-error: invalid source '%s'\n", source)
-		os.Exit(128)
-	}
-
-	// query-rsp loop
 	for stdin_reader := bufio.NewReader(os.Stdin); ; {
 		fmt.Fprintf(os.Stdout, "degree-of-separation --> ")
 
@@ -146,25 +137,30 @@ func main() {
 		}
 
 		// got something useful, let's see do we know it ?
-		if !sg.Contains(dest_name) {
+		dst_vertex, ok := sg.Lookup(dest_name)
+		if !ok {
 			log.Printf("error: '%s' doesn't exist\n", dest_name)
 			continue
 		}
 
-		// is there a path to destination ?
-		dst_vertex := sg.Index(dest_name)
-
-		if !all_paths.PathExists(dst_vertex) {
+		// is there a path to destination ? searching from both
+		// ends visits far fewer vertices than computing paths to
+		// every other vertex from the source
+		bp, err := traversal.GraphShortestPath(sg.G(), src_vertex, dst_vertex)
+		if err != nil {
 			log.Printf("no path to: '%s'\n", dest_name)
 			continue
 		}
 
+		if verbose_debug {
+			log.Printf("-- vertices visited: '%d' --\n", bp.Visited())
+		}
+
 		// yes there is a path, enumerate it...
-		src_dst_path := all_paths.PathTo(dst_vertex)
+		src_dst_path, _ := sg.NameAll(bp.Path())
 
 		fmt.Fprintf(os.Stdout, "source: %s, path-length: %d\npath:\n", source, len(src_dst_path)-1)
-		for _, v := range src_dst_path[1:] {
-			vertex_name, _ := sg.Name(v)
+		for _, vertex_name := range src_dst_path[1:] {
 			fmt.Fprintf(os.Stdout, "    %s\n", vertex_name)
 		}
 	}
@@ -72,8 +72,11 @@ func main() {
 		log.Printf("-- symbol-graph created. vertices: '%d', edges: '%d' --\n", sg_g.V(), sg_g.E())
 	}
 
+	// for suggesting names on a miss
+	name_index := sg.NameIndex()
+
 	// handle user queries from stdin
-	for sg_g, stdin_reader := sg.G(), bufio.NewReader(os.Stdin); ; {
+	for stdin_reader := bufio.NewReader(os.Stdin); ; {
 		// our prompt
 		fmt.Fprintf(os.Stdout, "graph-client --> ")
 
@@ -92,17 +95,22 @@ func main() {
 		}
 
 		// got something useful, let's see do we know it ?
-		if !sg.Contains(line_in) {
-			log.Printf("error: '%s'", err)
+		adj_list, err := sg.Neighbors(line_in)
+		if err != nil {
+			log.Printf("error: %s\n", err)
+
+			if candidates := name_index.Search(line_in, 2, 5); len(candidates) > 0 {
+				fmt.Printf("did you mean:\n")
+				for _, c := range candidates {
+					fmt.Printf("  %s\n", c.Name)
+				}
+			}
 			continue
 		}
 
 		// ok we do, dump named adjacency list
-		source := sg.Index(line_in)
 		fmt.Printf("%s\n", line_in)
-		adj_list := sg_g.Adj(source)
-		for _, v := range adj_list {
-			vname, _ := sg.Name(v)
+		for _, vname := range adj_list {
 			fmt.Printf("  %s\n", vname)
 		}
 	}
@@ -0,0 +1,393 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements typed attributes (properties) of vertices and
+// edges. attributes are kept in a sparse side table hanging off the
+// graph, which is only allocated when attributes are first used, so
+// that the plain topology (and Adj(...) in particular) isn't burdened
+// with them.
+//
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// kinds of attribute values
+//
+type AttrKind int8
+
+const (
+	AttrString AttrKind = iota // a string
+	AttrNumber                 // a float64
+	AttrTags                   // a list of strings
+)
+
+func (k AttrKind) String() string {
+	switch k {
+	case AttrString:
+		return "string"
+	case AttrNumber:
+		return "number"
+	case AttrTags:
+		return "tags"
+	}
+
+	return fmt.Sprintf("attr-kind(%d)", int8(k))
+}
+
+//
+// a typed attribute value, only the field corresponding to its 'Kind'
+// is meaningful. values are expected to be treated as immutable,
+// specifically 'Tags' may be shared between copies of an attribute
+// table.
+//
+type Attr struct {
+	Kind AttrKind
+	Str  string
+	Num  float64
+	Tags []string
+}
+
+func StringAttr(s string) Attr        { return Attr{Kind: AttrString, Str: s} }
+func NumberAttr(x float64) Attr       { return Attr{Kind: AttrNumber, Num: x} }
+func TagsAttr(tags ...string) Attr    { return Attr{Kind: AttrTags, Tags: tags} }
+func (a Attr) HasTag(tag string) bool { return a.Kind == AttrTags && index_of(a.Tags, tag) >= 0 }
+
+// two attributes are equal if they have the same kind, and value
+func (a Attr) Equal(b Attr) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case AttrNumber:
+		return a.Num == b.Num
+	case AttrTags:
+		if len(a.Tags) != len(b.Tags) {
+			return false
+		}
+		for i := range a.Tags {
+			if a.Tags[i] != b.Tags[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return a.Str == b.Str
+}
+
+func (a Attr) String() string {
+	switch a.Kind {
+	case AttrNumber:
+		return strconv.FormatFloat(a.Num, 'g', -1, 64)
+	case AttrTags:
+		return "[" + strings.Join(a.Tags, " ") + "]"
+	}
+
+	return a.Str
+}
+
+//
+// named attributes of a vertex or an edge
+//
+type AttrMap map[string]Attr
+
+//
+// attributes of vertices and edges of a graph. vertices are identified
+// by their ids, and edges by their endpoints, so parallel edges share
+// their attributes. for undirected graphs v-w and w-v are the same
+// edge.
+//
+// vertex ids aren't validated against the graph, that's left to the
+// clients.
+//
+type Attributes struct {
+	directed bool
+	vertex   map[int32]AttrMap
+	edge     map[edge_key_t]AttrMap
+}
+
+//
+// attribute table of the graph, which is created on first use
+//
+func (G *Graph) Attributes() *Attributes {
+	if G.attrs == nil {
+		G.attrs = new_attributes(false)
+	}
+	return G.attrs
+}
+
+func (G *Digraph) Attributes() *Attributes {
+	if G.attrs == nil {
+		G.attrs = new_attributes(true)
+	}
+	return G.attrs
+}
+
+//
+// set, lookup, and delete attribute 'name' of vertex 'v'
+//
+func (a *Attributes) SetVertex(v int32, name string, val Attr) {
+	set_attr(a.vertex, v, name, val)
+}
+
+func (a *Attributes) Vertex(v int32, name string) (val Attr, ok bool) {
+	val, ok = a.vertex[v][name]
+	return
+}
+
+func (a *Attributes) DeleteVertex(v int32, name string) {
+	delete_attr(a.vertex, v, name)
+}
+
+//
+// all attributes of vertex 'v', which must not be modified. nil if
+// there aren't any.
+//
+func (a *Attributes) VertexAttrs(v int32) AttrMap { return a.vertex[v] }
+
+//
+// set, lookup, and delete attribute 'name' of the edge 'v' -> 'w'
+// (or v-w for undirected graphs)
+//
+func (a *Attributes) SetEdge(v, w int32, name string, val Attr) {
+	set_attr(a.edge, a.edge_key(v, w), name, val)
+}
+
+func (a *Attributes) Edge(v, w int32, name string) (val Attr, ok bool) {
+	val, ok = a.edge[a.edge_key(v, w)][name]
+	return
+}
+
+func (a *Attributes) DeleteEdge(v, w int32, name string) {
+	delete_attr(a.edge, a.edge_key(v, w), name)
+}
+
+//
+// all attributes of the edge 'v' -> 'w', which must not be
+// modified. nil if there aren't any.
+//
+func (a *Attributes) EdgeAttrs(v, w int32) AttrMap { return a.edge[a.edge_key(v, w)] }
+
+//
+// names, and kinds of all the vertex, and edge attributes in use,
+// sorted by name. an error is returned if the same name is used with
+// different kinds, since most serialization formats can't express
+// that.
+//
+func (a *Attributes) Schema() (vertex, edge []AttrSchema, err error) {
+	if vertex, err = attr_schema("vertex", a.vertex); err != nil {
+		return
+	}
+	edge, err = attr_schema("edge", a.edge)
+
+	return
+}
+
+//
+// name, and kind of an attribute
+//
+type AttrSchema struct {
+	Name string
+	Kind AttrKind
+}
+
+//
+// private unexported stuff
+//
+
+type edge_key_t struct{ v, w int32 }
+
+func new_attributes(directed bool) *Attributes {
+	return &Attributes{
+		directed: directed,
+		vertex:   make(map[int32]AttrMap),
+		edge:     make(map[edge_key_t]AttrMap),
+	}
+}
+
+// undirected edges are keyed by their lower numbered endpoint first
+func (a *Attributes) edge_key(v, w int32) edge_key_t {
+	if !a.directed && v > w {
+		v, w = w, v
+	}
+	return edge_key_t{v, w}
+}
+
+func set_attr[K comparable](table map[K]AttrMap, key K, name string, val Attr) {
+	m, ok := table[key]
+	if !ok {
+		m = make(AttrMap)
+		table[key] = m
+	}
+	m[name] = val
+}
+
+func delete_attr[K comparable](table map[K]AttrMap, key K, name string) {
+	if m, ok := table[key]; ok {
+		if delete(m, name); len(m) == 0 {
+			delete(table, key)
+		}
+	}
+}
+
+func attr_schema[K comparable](what string, table map[K]AttrMap) (schema []AttrSchema, err error) {
+	kind_of := make(map[string]AttrKind)
+
+	for _, m := range table {
+		for name, val := range m {
+			if kind, seen := kind_of[name]; seen && kind != val.Kind {
+				return nil, fmt.Errorf("%s attribute '%s' is both %s and %s", what, name, kind, val.Kind)
+			}
+			kind_of[name] = val.Kind
+		}
+	}
+
+	for name, kind := range kind_of {
+		schema = append(schema, AttrSchema{name, kind})
+	}
+	sort.Slice(schema, func(i, j int) bool { return schema[i].Name < schema[j].Name })
+
+	return
+}
+
+//
+// drop attributes of the edge 'v' -> 'w' once the last such edge has
+// been removed from 'adj_v' (adjacency list of v)
+//
+func (a *Attributes) edge_removed(v, w int32, adj_v vertex_list_t) {
+	if a != nil && index_of(adj_v, w) < 0 {
+		delete(a.edge, a.edge_key(v, w))
+	}
+}
+
+//
+// attributes of the reverse digraph i.e. with each edge v -> w turned
+// into w -> v
+//
+func (a *Attributes) reverse() (rev *Attributes) {
+	if a == nil {
+		return nil
+	}
+
+	rev = new_attributes(a.directed)
+	for v, m := range a.vertex {
+		rev.vertex[v] = clone_attrs(m)
+	}
+	for k, m := range a.edge {
+		rev.edge[edge_key_t{k.w, k.v}] = clone_attrs(m)
+	}
+
+	return
+}
+
+//
+// attributes of a subgraph, where 'old_to_new' maps vertices of the
+// graph to the vertices of the subgraph (-1 for the ones left
+// out). attributes of edges with both endpoints in the subgraph are
+// retained, whether or not the edges themselves are.
+//
+func (a *Attributes) remap(old_to_new []int32) (sub *Attributes) {
+	if a == nil {
+		return nil
+	}
+
+	mapped := func(v int32) int32 {
+		if v < 0 || v >= int32(len(old_to_new)) {
+			return -1
+		}
+		return old_to_new[v]
+	}
+
+	sub = new_attributes(a.directed)
+	for v, m := range a.vertex {
+		if nv := mapped(v); nv >= 0 {
+			sub.vertex[nv] = clone_attrs(m)
+		}
+	}
+	for k, m := range a.edge {
+		if nv, nw := mapped(k.v), mapped(k.w); nv >= 0 && nw >= 0 {
+			sub.edge[sub.edge_key(nv, nw)] = clone_attrs(m)
+		}
+	}
+
+	return
+}
+
+//
+// copy of the attributes, without those of edges which aren't in 'G'
+// (any more)
+//
+func (a *Attributes) for_edges_of(G GraphOps) (c *Attributes) {
+	if a == nil {
+		return nil
+	}
+
+	c = new_attributes(a.directed)
+	for v, m := range a.vertex {
+		c.vertex[v] = clone_attrs(m)
+	}
+	for k, m := range a.edge {
+		c.edge[k] = clone_attrs(m)
+	}
+
+	c.prune_edges(G)
+	return
+}
+
+// drop attributes of edges which aren't in 'G'
+func (a *Attributes) prune_edges(G GraphOps) {
+	for k := range a.edge {
+		if k.v >= G.V() || k.w >= G.V() || !has_edge(G, k.v, k.w, a.directed) {
+			delete(a.edge, k)
+		}
+	}
+}
+
+func clone_attrs(m AttrMap) AttrMap {
+	c := make(AttrMap, len(m))
+	for name, val := range m {
+		c[name] = val
+	}
+	return c
+}
+
+func index_of[T comparable](list []T, x T) int {
+	for i, y := range list {
+		if x == y {
+			return i
+		}
+	}
+	return -1
+}
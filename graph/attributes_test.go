@@ -0,0 +1,308 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements the testing routine for vertex and edge
+// attributes
+//
+package graph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// a small flight network, with some attributes
+func create_attributed_digraph() *Digraph {
+	g := CreateDigraph(4)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(0, 2)
+	g.AddEdge(2, 3)
+
+	attrs := g.Attributes()
+	attrs.SetVertex(0, "city", StringAttr("new york"))
+	attrs.SetVertex(0, "hub", TagsAttr("oneworld", "star alliance"))
+	attrs.SetVertex(2, "elevation", NumberAttr(5431))
+	attrs.SetEdge(0, 1, "miles", NumberAttr(740))
+	attrs.SetEdge(2, 3, "carrier", StringAttr("ua"))
+
+	return g
+}
+
+// 'x' and 'y' have the same vertex and edge attributes over 'G'
+func cmp_attributes(G GraphOps, x, y *Attributes) bool {
+	cmp_maps := func(m1, m2 AttrMap) bool {
+		if len(m1) != len(m2) {
+			return false
+		}
+		for name, val := range m1 {
+			if other, ok := m2[name]; !ok || !val.Equal(other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for v := int32(0); v < G.V(); v++ {
+		if !cmp_maps(x.VertexAttrs(v), y.VertexAttrs(v)) {
+			return false
+		}
+		for _, w := range G.Adj(v) {
+			if !cmp_maps(x.EdgeAttrs(v, w), y.EdgeAttrs(v, w)) {
+				return false
+			}
+		}
+	}
+
+	return len(x.edge) == len(y.edge)
+}
+
+func TestAttributes(t *testing.T) {
+	g := New(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+
+	if g.attrs != nil {
+		t.Logf("attributes allocated before first use\n")
+		t.Fail()
+	}
+
+	// undirected edges are the same either way round
+	attrs := g.Attributes()
+	attrs.SetEdge(1, 0, "weight", NumberAttr(2.5))
+	if val, ok := attrs.Edge(0, 1, "weight"); !ok || val.Num != 2.5 {
+		t.Logf("unexpected edge attribute: %v, %v\n", val, ok)
+		t.Fail()
+	}
+
+	attrs.SetVertex(1, "tags", TagsAttr("a", "b"))
+	if val, _ := attrs.Vertex(1, "tags"); !val.HasTag("b") || val.HasTag("c") {
+		t.Logf("unexpected vertex attribute: %v\n", val)
+		t.Fail()
+	}
+
+	attrs.DeleteVertex(1, "tags")
+	if attrs.VertexAttrs(1) != nil {
+		t.Logf("vertex attributes survived deletion: %v\n", attrs.VertexAttrs(1))
+		t.Fail()
+	}
+
+	// attributes go away with the last of the parallel edges
+	g.RemoveEdge(0, 1)
+	if _, ok := attrs.Edge(0, 1, "weight"); !ok {
+		t.Logf("edge attribute dropped with a parallel edge remaining\n")
+		t.Fail()
+	}
+	g.RemoveEdge(1, 0)
+	if _, ok := attrs.Edge(0, 1, "weight"); ok {
+		t.Logf("edge attribute survived edge removal\n")
+		t.Fail()
+	}
+
+	// same name, different kinds
+	attrs.SetVertex(0, "x", StringAttr("1"))
+	attrs.SetVertex(2, "x", NumberAttr(1))
+	if _, _, err := attrs.Schema(); err == nil {
+		t.Logf("expected a schema error\n")
+		t.Fail()
+	}
+}
+
+func TestAttributesReverse(t *testing.T) {
+	g := create_attributed_digraph()
+	rev := g.Reverse()
+
+	if val, ok := rev.Attributes().Edge(1, 0, "miles"); !ok || val.Num != 740 {
+		t.Logf("edge attribute didn't follow the reversed edge\n")
+		t.Fail()
+	}
+	if _, ok := rev.Attributes().Edge(0, 1, "miles"); ok {
+		t.Logf("edge attribute left on the original direction\n")
+		t.Fail()
+	}
+
+	// reversing twice gets us back where we started
+	if !cmp_attributes(g, g.attrs, rev.Reverse().attrs) {
+		t.Logf("attributes changed across double reversal\n")
+		t.Fail()
+	}
+
+	// and the copies are independent
+	rev.Attributes().SetVertex(0, "city", StringAttr("newark"))
+	if val, _ := g.Attributes().Vertex(0, "city"); val.Str != "new york" {
+		t.Logf("reversed digraph shares attributes with the original\n")
+		t.Fail()
+	}
+}
+
+func TestAttributesRemap(t *testing.T) {
+	g := create_attributed_digraph()
+
+	// keep 0, and 2 as 1, and 0
+	sub := g.attrs.remap([]int32{1, -1, 0, -1})
+
+	if val, _ := sub.Vertex(1, "city"); val.Str != "new york" {
+		t.Logf("unexpected vertex attribute: %v\n", val)
+		t.Fail()
+	}
+	if val, _ := sub.Vertex(0, "elevation"); val.Num != 5431 {
+		t.Logf("unexpected vertex attribute: %v\n", val)
+		t.Fail()
+	}
+	if len(sub.edge) != 0 {
+		t.Logf("edges with a dropped endpoint kept their attributes: %v\n", sub.edge)
+		t.Fail()
+	}
+}
+
+func TestAttributesRoundTrip(t *testing.T) {
+	dg := create_attributed_digraph()
+
+	g := New(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 1)
+	g.Attributes().SetEdge(1, 2, "label", StringAttr("an edge label"))
+	g.Attributes().SetVertex(1, "score", NumberAttr(-0.125))
+
+	var buf bytes.Buffer
+
+	// jgf
+	dg.WriteJSON(&buf, nil)
+	if dg2, _, err := LoadDigraphFromJSON(&buf); err != nil || !cmp_attributes(dg, dg.attrs, dg2.Attributes()) {
+		t.Logf("jgf: digraph attributes didn't round trip, error: %v\n", err)
+		t.Fail()
+	}
+
+	buf.Reset()
+	g.WriteJSON(&buf, []string{"x", "y", "z"})
+	if g2, _, err := LoadFromJSON(&buf); err != nil || !cmp_attributes(g, g.attrs, g2.Attributes()) {
+		t.Logf("jgf: graph attributes didn't round trip, error: %v\n", err)
+		t.Fail()
+	}
+
+	// graphml
+	buf.Reset()
+	dg.WriteGraphML(&buf, nil)
+	if dg2, _, err := LoadDigraphFromGraphML(&buf); err != nil || !cmp_attributes(dg, dg.attrs, dg2.Attributes()) {
+		t.Logf("graphml: digraph attributes didn't round trip, error: %v\n", err)
+		t.Fail()
+	}
+
+	buf.Reset()
+	g.WriteGraphML(&buf, []string{"x", "y", "z"})
+	if g2, labels, err := LoadFromGraphML(&buf); err != nil || labels[2] != "z" || !cmp_attributes(g, g.attrs, g2.Attributes()) {
+		t.Logf("graphml: graph attributes didn't round trip, error: %v\n", err)
+		t.Fail()
+	}
+
+	// gexf
+	buf.Reset()
+	dg.WriteGEXF(&buf, nil)
+	if dg2, _, err := LoadDigraphFromGEXF(&buf); err != nil || !cmp_attributes(dg, dg.attrs, dg2.Attributes()) {
+		t.Logf("gexf: digraph attributes didn't round trip, error: %v\n", err)
+		t.Fail()
+	}
+
+	buf.Reset()
+	g.WriteGEXF(&buf, []string{"x", "y", "z"})
+	if g2, labels, err := LoadFromGEXF(&buf); err != nil || labels[2] != "z" || !cmp_attributes(g, g.attrs, g2.Attributes()) {
+		t.Logf("gexf: graph attributes didn't round trip, error: %v\n", err)
+		t.Fail()
+	}
+
+	// binary, with and without a checksum
+	for _, checksum := range []bool{false, true} {
+		buf.Reset()
+		dg.WriteBinary(&buf, checksum)
+		if dg2, err := LoadDigraphBinary(&buf); err != nil || !cmp_graph(dg, dg2) || !cmp_attributes(dg, dg.attrs, dg2.Attributes()) {
+			t.Logf("binary: digraph attributes didn't round trip, checksum: %v, error: %v\n", checksum, err)
+			t.Fail()
+		}
+
+		buf.Reset()
+		g.WriteBinary(&buf, checksum)
+		if g2, err := LoadBinary(&buf); err != nil || !cmp_graph(g, g2) || !cmp_attributes(g, g.attrs, g2.Attributes()) {
+			t.Logf("binary: graph attributes didn't round trip, checksum: %v, error: %v\n", checksum, err)
+			t.Fail()
+		}
+	}
+
+	// dot carries just the structure
+	buf.Reset()
+	g.WriteDot(&buf, nil)
+	if strings.Contains(buf.String(), "an edge label") {
+		t.Logf("dot: unexpected attributes in:\n%s\n", buf.String())
+		t.Fail()
+	}
+
+	// graphs without attributes stay that way
+	buf.Reset()
+	New(2).WriteJSON(&buf, nil)
+	if g2, _, _ := LoadFromJSON(&buf); g2.attrs != nil {
+		t.Logf("attributes allocated for a graph without them\n")
+		t.Fail()
+	}
+
+	buf.Reset()
+	New(2).WriteGEXF(&buf, nil)
+	if g2, _, _ := LoadFromGEXF(&buf); g2.attrs != nil {
+		t.Logf("attributes allocated for a graph without them\n")
+		t.Fail()
+	}
+
+	buf.Reset()
+	New(2).WriteBinary(&buf, false)
+	if g2, _ := LoadBinary(&buf); g2.attrs != nil {
+		t.Logf("attributes allocated for a graph without them\n")
+		t.Fail()
+	}
+
+	g.Attributes().SetVertex(0, "label", StringAttr("clash"))
+	if err := g.WriteGraphML(&buf, nil); err == nil {
+		t.Logf("expected an error for a vertex attribute named 'label'\n")
+		t.Fail()
+	}
+}
+
+func ExampleAttributes() {
+	g := New(2)
+	g.AddEdge(0, 1)
+
+	attrs := g.Attributes()
+	attrs.SetVertex(0, "name", StringAttr("alice"))
+	attrs.SetVertex(1, "roles", TagsAttr("admin", "dev"))
+	attrs.SetEdge(1, 0, "since", NumberAttr(2014))
+
+	g.WriteJSON(os.Stdout, nil)
+
+	// Output:
+	// {"graph":{"directed":false,"metadata":{"vertices":2,"edges":1},"nodes":{"0":{"metadata":{"name":"alice"}},"1":{"metadata":{"roles":["admin","dev"]}}},"edges":[{"source":"0","target":"1","metadata":{"since":2014}}]}}
+}
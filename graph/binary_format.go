@@ -0,0 +1,799 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements a compact, versioned binary on-disk format for
+// graphs and digraphs. adjacency lists are stored in the
+// compressed-sparse-row (csr) layout, which allows loading a graph
+// without any text parsing. all integers are little-endian, and the
+// layout is as following
+//
+//     <header>
+//         magic       [4]byte  "CUGB"
+//         version     uint16
+//         flags       uint16   (bit-0: directed, bit-1: checksum,
+//                              bit-2: attributes)
+//         V           uint32   number of vertices
+//         E           uint32   number of edges
+//         N           uint32   number of adjacency entries
+//         A           uint32   size of the attribute section in bytes,
+//                              zero when bit-2 of flags isn't set
+//     <offsets>       [V+1]uint32
+//     <targets>       [N]uint32
+//     <attributes>    [A]byte  present only when bit-2 of flags is set
+//     <checksum>      uint32   crc32 (castagnoli) of everything above,
+//                              present only when bit-1 of flags is set
+//
+// adjacency list of vertex 'v' is targets[offsets[v]:offsets[v+1]].
+// for undirected graphs each edge appears in both adjacency lists,
+// and thus N == 2*E, while for digraphs N == E.
+//
+// the attribute section holds vertex, and then edge attributes as
+// following, with strings being a uint32 length followed by as many
+// bytes
+//
+//     <vertices>      uint32   number of vertices with attributes
+//         vertex      uint32   followed by its <attrs>
+//     <edges>         uint32   number of edges with attributes
+//         v, w        uint32   followed by attrs of the edge v-w (v -> w)
+//     <attrs>         uint32   number of attributes
+//         name        string
+//         kind        uint8    AttrString, AttrNumber or AttrTags
+//         value       string, float64 bits as uint64, or a uint32
+//                              count followed by as many strings
+//
+// the attribute section was introduced in version 2, in version 1 'A'
+// is reserved, and must be zero. graphs without attributes are still
+// written as version 1.
+//
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+const (
+	binary_magic       = "CUGB"
+	binary_version     = 2
+	binary_min_version = 1
+
+	binary_flag_directed = 1 << 0
+	binary_flag_checksum = 1 << 1
+	binary_flag_attrs    = 1 << 2
+
+	binary_header_size = 24
+
+	// read adjacency data in chunks of these many entries
+	binary_chunk_entries = 16 * 1024
+)
+
+var binary_crc_table = crc32.MakeTable(crc32.Castagnoli)
+
+// fixed size header of the binary format
+type binary_header_t struct {
+	version uint16
+	flags   uint16
+	v       uint32
+	e       uint32
+	n       uint32
+	a       uint32
+}
+
+// the decoded, validated, csr representation of a graph
+type csr_t struct {
+	hdr     binary_header_t
+	offsets []uint32
+	targets []int32
+	attrs   *Attributes
+}
+
+func (hdr *binary_header_t) directed() bool  { return hdr.flags&binary_flag_directed != 0 }
+func (hdr *binary_header_t) checksum() bool  { return hdr.flags&binary_flag_checksum != 0 }
+func (hdr *binary_header_t) has_attrs() bool { return hdr.flags&binary_flag_attrs != 0 }
+
+//
+// write an undirected graph, and its attributes (if any) in the
+// binary format to 'w'. when 'checksum' is true, a crc32 of the
+// contents is appended, and verified at load time.
+//
+func (G *Graph) WriteBinary(w io.Writer, checksum bool) error {
+	return write_binary(w, G, false, checksum, G.attrs)
+}
+
+//
+// write a digraph, and its attributes (if any) in the binary format
+// to 'w'. when 'checksum' is true, a crc32 of the contents is
+// appended, and verified at load time.
+//
+func (G *Digraph) WriteBinary(w io.Writer, checksum bool) error {
+	return write_binary(w, G, true, checksum, G.attrs)
+}
+
+//
+// load an undirected graph, written with WriteBinary(...), from
+// 'src'. truncated or corrupt input is rejected with a descriptive
+// error.
+//
+func LoadBinary(src io.Reader) (G *Graph, err error) {
+	var csr *csr_t
+
+	if csr, err = read_binary(src); err != nil {
+		return
+	}
+
+	if csr.hdr.directed() {
+		err = fmt.Errorf("binary: expected an undirected graph, found a digraph")
+		return
+	}
+
+	G = &Graph{
+		v:     int32(csr.hdr.v),
+		e:     int32(csr.hdr.e),
+		adj:   csr.adjacency(),
+		attrs: csr.attrs,
+	}
+
+	return
+}
+
+//
+// load a digraph, written with WriteBinary(...), from 'src'.
+// truncated or corrupt input is rejected with a descriptive error.
+//
+func LoadDigraphBinary(src io.Reader) (G *Digraph, err error) {
+	var csr *csr_t
+
+	if csr, err = read_binary(src); err != nil {
+		return
+	}
+
+	if !csr.hdr.directed() {
+		err = fmt.Errorf("binary: expected a digraph, found an undirected graph")
+		return
+	}
+
+	G = &Digraph{
+		v:     int32(csr.hdr.v),
+		e:     int32(csr.hdr.e),
+		adj:   csr.adjacency(),
+		attrs: csr.attrs,
+	}
+
+	return
+}
+
+//
+// check that 'src' holds exactly one well-formed graph or digraph in
+// the binary format, without building it. for undirected graphs, the
+// adjacency lists must also be symmetric, and attributes, if any, must
+// refer to vertices and edges of the graph. returns nil if all is
+// well, and a descriptive error otherwise.
+//
+func ValidateBinary(src io.Reader) (err error) {
+	r := bufio.NewReader(src)
+
+	if _, err = read_binary(r); err != nil {
+		return
+	}
+
+	if _, err = r.ReadByte(); err != io.EOF {
+		return fmt.Errorf("binary: unexpected trailing data after graph")
+	}
+
+	return nil
+}
+
+//
+// carve per-vertex adjacency lists out of the csr targets. each list
+// is capped at its own length, so that subsequent AddEdge(...) calls
+// reallocate instead of clobbering the neighbouring list.
+//
+func (csr *csr_t) adjacency() []vertex_list_t {
+	adj := make([]vertex_list_t, csr.hdr.v)
+	for v := range adj {
+		lo, hi := csr.offsets[v], csr.offsets[v+1]
+		adj[v] = vertex_list_t(csr.targets[lo:hi:hi])
+	}
+
+	return adj
+}
+
+// common routine for writing graphs and digraphs in the binary format
+func write_binary(dst io.Writer, G GraphOps, directed, checksum bool, attrs *Attributes) (err error) {
+	var sink io.Writer
+	var crc hash.Hash32
+	var attr_section []byte
+
+	if attr_section, err = encode_binary_attrs(attrs); err != nil {
+		return
+	}
+
+	bw := bufio.NewWriter(dst)
+	sink = bw
+	if checksum {
+		crc = crc32.New(binary_crc_table)
+		sink = io.MultiWriter(bw, crc)
+	}
+
+	hdr := binary_header_t{version: binary_min_version}
+	if directed {
+		hdr.flags |= binary_flag_directed
+	}
+	if checksum {
+		hdr.flags |= binary_flag_checksum
+	}
+	if len(attr_section) > 0 {
+		hdr.version = binary_version
+		hdr.flags |= binary_flag_attrs
+		hdr.a = uint32(len(attr_section))
+	}
+
+	hdr.v, hdr.e = uint32(G.V()), uint32(G.E())
+	for v := int32(0); v < G.V(); v++ {
+		hdr.n += uint32(len(G.Adj(v)))
+	}
+
+	var buf [binary_header_size]byte
+	copy(buf[0:4], binary_magic)
+	binary.LittleEndian.PutUint16(buf[4:], hdr.version)
+	binary.LittleEndian.PutUint16(buf[6:], hdr.flags)
+	binary.LittleEndian.PutUint32(buf[8:], hdr.v)
+	binary.LittleEndian.PutUint32(buf[12:], hdr.e)
+	binary.LittleEndian.PutUint32(buf[16:], hdr.n)
+	binary.LittleEndian.PutUint32(buf[20:], hdr.a)
+	sink.Write(buf[:])
+
+	// offsets
+	offset := uint32(0)
+	binary.LittleEndian.PutUint32(buf[0:], offset)
+	sink.Write(buf[0:4])
+	for v := int32(0); v < G.V(); v++ {
+		offset += uint32(len(G.Adj(v)))
+		binary.LittleEndian.PutUint32(buf[0:], offset)
+		sink.Write(buf[0:4])
+	}
+
+	// targets
+	for v := int32(0); v < G.V(); v++ {
+		for _, w := range G.Adj(v) {
+			binary.LittleEndian.PutUint32(buf[0:], uint32(w))
+			sink.Write(buf[0:4])
+		}
+	}
+
+	sink.Write(attr_section)
+
+	if checksum {
+		binary.LittleEndian.PutUint32(buf[0:], crc.Sum32())
+		bw.Write(buf[0:4])
+	}
+
+	// errors on the buffered writer are sticky
+	return bw.Flush()
+}
+
+//
+// common routine for reading graphs and digraphs in the binary
+// format. everything read is validated before being handed out.
+//
+func read_binary(src io.Reader) (csr *csr_t, err error) {
+	var crc hash.Hash32
+
+	r := src
+	csr = &csr_t{}
+
+	// header
+	var buf [binary_header_size]byte
+	if _, err = io.ReadFull(r, buf[:]); err != nil {
+		return nil, binary_read_error("header", err)
+	}
+
+	hdr := &csr.hdr
+	if *hdr, err = decode_binary_header(buf[:]); err != nil {
+		return nil, err
+	}
+
+	if hdr.checksum() {
+		crc = crc32.New(binary_crc_table)
+		crc.Write(buf[:])
+		r = io.TeeReader(src, crc)
+	}
+
+	// offsets
+	if csr.offsets, err = read_uint32s(r, uint64(hdr.v)+1); err != nil {
+		return nil, binary_read_error("offsets", err)
+	}
+
+	if err = hdr.check_offsets(csr.offsets); err != nil {
+		return nil, err
+	}
+
+	// targets
+	var targets []uint32
+	if targets, err = read_uint32s(r, uint64(hdr.n)); err != nil {
+		return nil, binary_read_error("targets", err)
+	}
+
+	csr.targets = make([]int32, len(targets))
+	for i, w := range targets {
+		csr.targets[i] = int32(w)
+	}
+
+	if err = hdr.check_targets(csr.targets); err != nil {
+		return nil, err
+	}
+
+	// attributes, decoded once the checksum has vouched for them
+	var attr_section bytes.Buffer
+	if hdr.has_attrs() {
+		if _, err = io.CopyN(&attr_section, r, int64(hdr.a)); err != nil {
+			return nil, binary_read_error("attributes", err)
+		}
+	}
+
+	// checksum
+	if hdr.checksum() {
+		want := crc.Sum32()
+
+		if _, err = io.ReadFull(src, buf[0:4]); err != nil {
+			return nil, binary_read_error("checksum", err)
+		}
+
+		if got := binary.LittleEndian.Uint32(buf[0:]); got != want {
+			return nil, fmt.Errorf("binary: checksum mismatch, stored: %#08x, computed: %#08x", got, want)
+		}
+	}
+
+	if !hdr.directed() {
+		if err = csr.check_symmetric(); err != nil {
+			return nil, err
+		}
+	}
+
+	if hdr.has_attrs() {
+		if csr.attrs, err = csr.decode_attrs(attr_section.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	return csr, nil
+}
+
+//
+// decode and sanity check the fixed size header at the start of
+// 'buf'
+//
+func decode_binary_header(buf []byte) (hdr binary_header_t, err error) {
+	if string(buf[0:4]) != binary_magic {
+		err = fmt.Errorf("binary: bad magic %q, not a binary graph", buf[0:4])
+		return
+	}
+
+	hdr.version = binary.LittleEndian.Uint16(buf[4:])
+	hdr.flags = binary.LittleEndian.Uint16(buf[6:])
+	hdr.v = binary.LittleEndian.Uint32(buf[8:])
+	hdr.e = binary.LittleEndian.Uint32(buf[12:])
+	hdr.n = binary.LittleEndian.Uint32(buf[16:])
+	hdr.a = binary.LittleEndian.Uint32(buf[20:])
+
+	err = hdr.validate()
+	return
+}
+
+//
+// total size of a file in the binary format described by this header,
+// including the trailing checksum if any
+//
+func (hdr *binary_header_t) size() int64 {
+	size := int64(binary_header_size) + 4*(int64(hdr.v)+1) + 4*int64(hdr.n) + int64(hdr.a)
+	if hdr.checksum() {
+		size += 4
+	}
+
+	return size
+}
+
+// offsets must start at 0, never decrease, and cover all entries
+func (hdr *binary_header_t) check_offsets(offsets []uint32) error {
+	if offsets[0] != 0 {
+		return fmt.Errorf("binary: offset of vertex 0 is %d, expected 0", offsets[0])
+	}
+
+	for v := uint32(0); v < hdr.v; v++ {
+		if offsets[v+1] < offsets[v] {
+			return fmt.Errorf("binary: offsets decrease at vertex %d (%d < %d)", v+1, offsets[v+1], offsets[v])
+		}
+	}
+
+	if offsets[hdr.v] != hdr.n {
+		return fmt.Errorf("binary: last offset is %d, expected %d adjacency entries", offsets[hdr.v], hdr.n)
+	}
+
+	return nil
+}
+
+// every adjacency entry must refer to a valid vertex
+func (hdr *binary_header_t) check_targets(targets []int32) error {
+	for i, w := range targets {
+		if w < 0 || uint32(w) >= hdr.v {
+			return fmt.Errorf("binary: adjacency entry %d refers to vertex %d, which is out of range [0, %d)", i, uint32(w), hdr.v)
+		}
+	}
+
+	return nil
+}
+
+//
+// adjacency of an undirected graph must be symmetric i.e. 'w' appears
+// in adj[v] as many times as 'v' does in adj[w]. each self-loop
+// appears twice in the adjacency list of its vertex, so these must
+// come in pairs. the reverse adjacency, built in vertex order and thus
+// sorted, is compared with a sorted copy of each adjacency list.
+//
+func (csr *csr_t) check_symmetric() error {
+	V := csr.hdr.v
+
+	rev_offsets := make([]uint32, V+1)
+	for _, w := range csr.targets {
+		rev_offsets[w+1] += 1
+	}
+	for v := uint32(0); v < V; v++ {
+		rev_offsets[v+1] += rev_offsets[v]
+	}
+
+	rev := make([]int32, len(csr.targets))
+	next := append([]uint32(nil), rev_offsets[:V]...)
+	for v := uint32(0); v < V; v++ {
+		for _, w := range csr.targets[csr.offsets[v]:csr.offsets[v+1]] {
+			rev[next[w]] = int32(v)
+			next[w] += 1
+		}
+	}
+
+	var adj []int32
+	for v := uint32(0); v < V; v++ {
+		adj = append(adj[:0], csr.targets[csr.offsets[v]:csr.offsets[v+1]]...)
+		back := rev[rev_offsets[v]:rev_offsets[v+1]]
+
+		if len(adj) != len(back) {
+			return fmt.Errorf("binary: adjacency of vertex %d isn't symmetric", v)
+		}
+
+		sort.Slice(adj, func(i, j int) bool { return adj[i] < adj[j] })
+
+		loops := 0
+		for i, w := range adj {
+			if w != back[i] {
+				return fmt.Errorf("binary: adjacency of vertex %d isn't symmetric", v)
+			}
+			if uint32(w) == v {
+				loops += 1
+			}
+		}
+
+		if loops%2 != 0 {
+			return fmt.Errorf("binary: vertex %d has an unpaired self-loop entry", v)
+		}
+	}
+
+	return nil
+}
+
+//
+// encode the attribute section, vertices, edges and attribute names
+// are written in sorted order, so that the output is deterministic.
+// nothing is returned when there aren't any attributes.
+//
+func encode_binary_attrs(attrs *Attributes) (section []byte, err error) {
+	if attrs == nil || (len(attrs.vertex) == 0 && len(attrs.edge) == 0) {
+		return
+	}
+
+	vertices := make([]int32, 0, len(attrs.vertex))
+	for v := range attrs.vertex {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool { return vertices[i] < vertices[j] })
+
+	edges := make([]edge_key_t, 0, len(attrs.edge))
+	for k := range attrs.edge {
+		edges = append(edges, k)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].v != edges[j].v {
+			return edges[i].v < edges[j].v
+		}
+		return edges[i].w < edges[j].w
+	})
+
+	le := binary.LittleEndian
+
+	section = le.AppendUint32(section, uint32(len(vertices)))
+	for _, v := range vertices {
+		section = le.AppendUint32(section, uint32(v))
+		section = append_binary_attrs(section, attrs.vertex[v])
+	}
+
+	section = le.AppendUint32(section, uint32(len(edges)))
+	for _, k := range edges {
+		section = le.AppendUint32(section, uint32(k.v))
+		section = le.AppendUint32(section, uint32(k.w))
+		section = append_binary_attrs(section, attrs.edge[k])
+	}
+
+	if uint64(len(section)) > math.MaxUint32 {
+		return nil, fmt.Errorf("binary: attribute section of %d bytes is too large", len(section))
+	}
+
+	return
+}
+
+// append attributes in 'm' to 'buf', sorted by name
+func append_binary_attrs(buf []byte, m AttrMap) []byte {
+	le := binary.LittleEndian
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf = le.AppendUint32(buf, uint32(len(names)))
+	for _, name := range names {
+		val := m[name]
+
+		buf = append_binary_string(buf, name)
+		buf = append(buf, byte(val.Kind))
+
+		switch val.Kind {
+		case AttrString:
+			buf = append_binary_string(buf, val.Str)
+		case AttrNumber:
+			buf = le.AppendUint64(buf, math.Float64bits(val.Num))
+		case AttrTags:
+			buf = le.AppendUint32(buf, uint32(len(val.Tags)))
+			for _, tag := range val.Tags {
+				buf = append_binary_string(buf, tag)
+			}
+		}
+	}
+
+	return buf
+}
+
+func append_binary_string(buf []byte, s string) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	return append(buf, s...)
+}
+
+//
+// decode the attribute section of a csr. attributes must refer to
+// vertices, and edges of the graph, and the section must be consumed
+// entirely.
+//
+func (csr *csr_t) decode_attrs(section []byte) (attrs *Attributes, err error) {
+	d := &binary_attr_decoder_t{buf: section}
+	attrs = new_attributes(csr.hdr.directed())
+
+	nv := d.uint32()
+	for i := uint32(0); i < nv && d.err == nil; i++ {
+		v := d.uint32()
+		if d.err == nil && v >= csr.hdr.v {
+			return nil, binary_attr_error("vertex %d is out of range [0, %d)", v, csr.hdr.v)
+		}
+		d.attrs(func(name string, val Attr) { attrs.SetVertex(int32(v), name, val) })
+	}
+
+	ne := d.uint32()
+	for i := uint32(0); i < ne && d.err == nil; i++ {
+		v, w := d.uint32(), d.uint32()
+		if d.err == nil && !csr.has_edge(v, w) {
+			return nil, binary_attr_error("edge %d-%d isn't in the graph", v, w)
+		}
+		d.attrs(func(name string, val Attr) { attrs.SetEdge(int32(v), int32(w), name, val) })
+	}
+
+	switch {
+	case d.err != nil:
+		return nil, d.err
+	case len(d.buf) != 0:
+		return nil, binary_attr_error("%d trailing bytes", len(d.buf))
+	}
+
+	return attrs, nil
+}
+
+// true if 'w' is in the adjacency list of 'v'
+func (csr *csr_t) has_edge(v, w uint32) bool {
+	if v >= csr.hdr.v {
+		return false
+	}
+
+	for _, x := range csr.targets[csr.offsets[v]:csr.offsets[v+1]] {
+		if uint32(x) == w {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// bounds checked reader of the attribute section. the first error
+// sticks, and subsequent reads return zero values.
+//
+type binary_attr_decoder_t struct {
+	buf []byte
+	err error
+}
+
+func (d *binary_attr_decoder_t) next(n uint64) (b []byte) {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.buf)) {
+		d.err = binary_attr_error("truncated section")
+		return nil
+	}
+
+	b, d.buf = d.buf[:n], d.buf[n:]
+	return
+}
+
+func (d *binary_attr_decoder_t) uint32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *binary_attr_decoder_t) string() string {
+	return string(d.next(uint64(d.uint32())))
+}
+
+// decode a list of attributes, handing each to 'set'
+func (d *binary_attr_decoder_t) attrs(set func(name string, val Attr)) {
+	count := d.uint32()
+	for i := uint32(0); i < count && d.err == nil; i++ {
+		name := d.string()
+
+		var kind AttrKind
+		if b := d.next(1); b != nil {
+			kind = AttrKind(b[0])
+		}
+
+		var val Attr
+		switch kind {
+		case AttrString:
+			val = StringAttr(d.string())
+
+		case AttrNumber:
+			if b := d.next(8); b != nil {
+				val = NumberAttr(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+			}
+
+		case AttrTags:
+			n := d.uint32()
+			tags := []string{}
+			for j := uint32(0); j < n && d.err == nil; j++ {
+				tags = append(tags, d.string())
+			}
+			val = TagsAttr(tags...)
+
+		default:
+			if d.err == nil {
+				d.err = binary_attr_error("attribute %q has unknown kind %d", name, kind)
+			}
+		}
+
+		if d.err == nil {
+			set(name, val)
+		}
+	}
+}
+
+func binary_attr_error(format string, args ...interface{}) error {
+	return fmt.Errorf("binary: malformed attributes: "+format, args...)
+}
+
+// sanity checks on a freshly read header
+func (hdr *binary_header_t) validate() error {
+	const max_count = uint32(1<<31 - 1)
+
+	switch {
+	case hdr.version < binary_min_version || hdr.version > binary_version:
+		return fmt.Errorf("binary: unsupported version %d, expected %d to %d", hdr.version, binary_min_version, binary_version)
+
+	case hdr.flags&^(binary_flag_directed|binary_flag_checksum|binary_flag_attrs) != 0:
+		return fmt.Errorf("binary: unknown flags %#04x", hdr.flags)
+
+	case hdr.has_attrs() && hdr.version < 2:
+		return fmt.Errorf("binary: attributes in a version %d file", hdr.version)
+
+	case !hdr.has_attrs() && hdr.a != 0:
+		return fmt.Errorf("binary: reserved header field is %d, expected 0", hdr.a)
+
+	case hdr.has_attrs() && hdr.a == 0:
+		return fmt.Errorf("binary: empty attribute section")
+
+	case hdr.v > max_count || hdr.e > max_count:
+		return fmt.Errorf("binary: vertex count %d or edge count %d is out of range", hdr.v, hdr.e)
+
+	case hdr.directed() && hdr.n != hdr.e:
+		return fmt.Errorf("binary: digraph with %d edges has %d adjacency entries", hdr.e, hdr.n)
+
+	case !hdr.directed() && uint64(hdr.n) != 2*uint64(hdr.e):
+		return fmt.Errorf("binary: graph with %d edges has %d adjacency entries, expected %d", hdr.e, hdr.n, 2*uint64(hdr.e))
+	}
+
+	return nil
+}
+
+//
+// read 'count' little-endian uint32 values from 'r'. values are read
+// in chunks, so that a corrupt count in the header results in an
+// early truncation error rather than a huge up-front allocation.
+//
+func read_uint32s(r io.Reader, count uint64) (vals []uint32, err error) {
+	buf := make([]byte, 4*binary_chunk_entries)
+
+	for remaining := count; remaining > 0; {
+		chunk := remaining
+		if chunk > binary_chunk_entries {
+			chunk = binary_chunk_entries
+		}
+
+		if _, err = io.ReadFull(r, buf[:4*chunk]); err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < chunk; i++ {
+			vals = append(vals, binary.LittleEndian.Uint32(buf[4*i:]))
+		}
+
+		remaining -= chunk
+	}
+
+	return
+}
+
+// describe a failure to read a section of the binary format
+func binary_read_error(section string, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("binary: truncated input while reading %s", section)
+	}
+
+	return fmt.Errorf("binary: error while reading %s: %s", section, err)
+}
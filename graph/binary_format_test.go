@@ -0,0 +1,288 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+//
+// graphs and digraphs must come back unchanged from the binary
+// format, with and without checksums
+//
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, checksum := range []bool{false, true} {
+		for i, graph := range graphs {
+			g1 := create_test_graph(&graph.graph_defn)
+
+			var buf bytes.Buffer
+			if err := g1.WriteBinary(&buf, checksum); err != nil {
+				t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+				t.FailNow()
+			}
+
+			if err := ValidateBinary(bytes.NewReader(buf.Bytes())); err != nil {
+				t.Logf("test-graph: %d, validation failed: %s\n", i, err)
+				t.Fail()
+			}
+
+			g2, err := LoadBinary(&buf)
+			if err != nil || !cmp_graph(g1, g2) || g1.String() != g2.String() {
+				t.Logf("test-graph: %d, checksum: %v, round-trip failed, error: %v\n", i, checksum, err)
+				t.Fail()
+			}
+		}
+
+		d1 := create_xml_test_digraph()
+
+		var buf bytes.Buffer
+		d1.WriteBinary(&buf, checksum)
+
+		d2, err := LoadDigraphBinary(&buf)
+		if err != nil || d1.String() != d2.String() {
+			t.Logf("digraph: checksum: %v, round-trip failed, error: %v\n", checksum, err)
+			t.Fail()
+		}
+	}
+}
+
+//
+// edges added after loading must not clobber the adjacency lists of
+// other vertices which share the same backing storage
+//
+func TestBinaryLoadThenAddEdge(t *testing.T) {
+	g1 := create_bench_graph(16)
+
+	var buf bytes.Buffer
+	g1.WriteBinary(&buf, false)
+	g2, _ := LoadBinary(&buf)
+
+	g1.AddEdge(3, 11)
+	g2.AddEdge(3, 11)
+
+	if g1.String() != g2.String() {
+		t.Logf("expected:\n%s\ngot:\n%s\n", g1, g2)
+		t.Fail()
+	}
+}
+
+//
+// every possible truncation, and a variety of corruptions must be
+// rejected
+//
+func TestBinaryCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	create_bench_graph(32).WriteBinary(&buf, true)
+	good := buf.Bytes()
+
+	for i := 0; i < len(good); i++ {
+		err := ValidateBinary(bytes.NewReader(good[:i]))
+		if err == nil || !strings.Contains(err.Error(), "truncated") {
+			t.Logf("truncated at: %d, unexpected error: %v\n", i, err)
+			t.Fail()
+		}
+	}
+
+	corrupt := func(at int, val byte) []byte {
+		bad := append([]byte(nil), good...)
+		bad[at] = val
+		return bad
+	}
+
+	test_cases := []struct {
+		data   []byte
+		reason string
+	}{
+		{corrupt(0, 'X'), "bad magic"},
+		{corrupt(4, 9), "unsupported version"},
+		{corrupt(6, 0xff), "unknown flags"},
+		{corrupt(12, 7), "adjacency entries"},
+		{corrupt(20, 1), "reserved"},
+		{corrupt(binary_header_size, 1), "offset of vertex 0"},
+		{corrupt(binary_header_size+4*5, 0xff), "offsets decrease"},
+		{corrupt(binary_header_size+4*33+2, 1), "out of range"},
+		{corrupt(binary_header_size+4*33, 5), "checksum mismatch"},
+		{append(append([]byte(nil), good...), 0), "trailing data"},
+	}
+
+	for i, tc := range test_cases {
+		err := ValidateBinary(bytes.NewReader(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.reason) {
+			t.Logf("test-case: %d, expected: '%s', got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+
+	// graph vs. digraph mixup
+	buf.Reset()
+	create_xml_test_digraph().WriteBinary(&buf, false)
+	if _, err := LoadBinary(&buf); err == nil {
+		t.Logf("digraph loaded as an undirected graph\n")
+		t.Fail()
+	}
+}
+
+//
+// graphs without attributes are written in the original version 1
+// format, and a malformed attribute section must be rejected
+//
+func TestBinaryAttributes(t *testing.T) {
+	var buf bytes.Buffer
+
+	g := New(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.WriteBinary(&buf, false)
+	if v := binary.LittleEndian.Uint16(buf.Bytes()[4:]); v != binary_min_version {
+		t.Logf("graph without attributes written as version %d\n", v)
+		t.Fail()
+	}
+
+	g.Attributes().SetVertex(2, "w", NumberAttr(0.5))
+	g.Attributes().SetEdge(1, 0, "label", StringAttr("x"))
+
+	buf.Reset()
+	g.WriteBinary(&buf, false)
+	good := buf.Bytes()
+
+	// attribute section follows the targets, field offsets within it
+	// are: vertex at 4, kind of "w" at 17, and 'w' of the edge at 34
+	base := len(good) - int(binary.LittleEndian.Uint32(good[20:]))
+
+	corrupt := func(at int, val byte) []byte {
+		bad := append([]byte(nil), good...)
+		bad[at] = val
+		return bad
+	}
+
+	trailing := append(corrupt(20, good[20]+1), 0)
+
+	test_cases := []struct {
+		data   []byte
+		reason string
+	}{
+		{corrupt(4, 1), "attributes in a version 1 file"},
+		{corrupt(6, 0), "reserved"},
+		{corrupt(base+4, 7), "vertex 7 is out of range"},
+		{corrupt(base+17, 9), "unknown kind"},
+		{corrupt(base+34, 0), "edge 0-0 isn't in the graph"},
+		{good[:len(good)-1], "truncated"},
+		{trailing, "trailing bytes"},
+	}
+
+	for i, tc := range test_cases {
+		err := ValidateBinary(bytes.NewReader(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.reason) {
+			t.Logf("test-case: %d, expected: '%s', got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+}
+
+//
+// adjacency lists of undirected graphs must agree with each other,
+// and with the edge count, even without a checksum to vouch for them
+//
+func TestBinaryAsymmetric(t *testing.T) {
+	// a binary graph, as is, with adjacency lists 'adj'
+	encode := func(E uint32, adj [][]uint32) []byte {
+		var offsets, targets []uint32
+
+		offsets = append(offsets, 0)
+		for _, list := range adj {
+			targets = append(targets, list...)
+			offsets = append(offsets, uint32(len(targets)))
+		}
+
+		buf := []byte(binary_magic)
+		for _, x := range []uint16{binary_version, 0} {
+			buf = binary.LittleEndian.AppendUint16(buf, x)
+		}
+		for _, x := range []uint32{uint32(len(adj)), E, uint32(len(targets)), 0} {
+			buf = binary.LittleEndian.AppendUint32(buf, x)
+		}
+		for _, x := range append(offsets, targets...) {
+			buf = binary.LittleEndian.AppendUint32(buf, x)
+		}
+
+		return buf
+	}
+
+	test_cases := []struct {
+		data   []byte
+		reason string
+	}{
+		{encode(1, [][]uint32{{1}, {1}}), "isn't symmetric"},
+		{encode(2, [][]uint32{{1, 1}, {0, 2}, {}}), "isn't symmetric"},
+		{encode(2, [][]uint32{{0, 1}, {0, 1}}), "unpaired self-loop"},
+		{encode(2, [][]uint32{{1}, {0}}), "adjacency entries"},
+		{encode(2, [][]uint32{{1, 0, 0}, {0}}), ""},
+	}
+
+	for i, tc := range test_cases {
+		err := ValidateBinary(bytes.NewReader(tc.data))
+		if len(tc.reason) == 0 && err != nil {
+			t.Logf("test-case: %d, unexpected error: %s\n", i, err)
+			t.Fail()
+		}
+
+		if len(tc.reason) > 0 && (err == nil || !strings.Contains(err.Error(), tc.reason)) {
+			t.Logf("test-case: %d, expected: '%s', got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+
+		if _, load_err := LoadBinary(bytes.NewReader(tc.data)); (load_err == nil) != (err == nil) {
+			t.Logf("test-case: %d, validation error: %v, load error: %v\n", i, err, load_err)
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkLoadFromReader(b *testing.B) {
+	var buf bytes.Buffer
+	create_bench_graph(4096).WriteTo(&buf)
+	data := buf.Bytes()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		LoadFromReader(bufio.NewReader(bytes.NewReader(data)))
+	}
+}
+
+func BenchmarkLoadBinary(b *testing.B) {
+	var buf bytes.Buffer
+	create_bench_graph(4096).WriteBinary(&buf, true)
+	data := buf.Bytes()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		LoadBinary(bytes.NewReader(data))
+	}
+}
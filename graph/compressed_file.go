@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements transparent decompression of graph data files.
+// bzip2 and gzip compressed files are recognized by their magic
+// bytes, and decompressed on the fly.
+//
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+//
+// a bzip2 stream starts with "BZh", a block size of '1' to '9', and
+// then either the magic of the first block (bcd(pi)), or that of the
+// end of stream (bcd(sqrt(pi))) if it is empty
+//
+var (
+	bzip2_magic      = []byte("BZh")
+	bzip2_block      = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2_stream_end = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+	gzip_magic       = []byte{0x1f, 0x8b}
+)
+
+//
+// a decompressing reader along with the underlying file, closing it
+// closes both
+//
+type compressed_file_t struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (cf *compressed_file_t) Close() (err error) {
+	for _, c := range cf.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return
+}
+
+//
+// open a graph data file for reading, decompressing it on the fly if
+// it is bzip2 or gzip compressed. compression is detected by the
+// magic bytes at the start of the file, and a file whose extension
+// (.bz2, .gz) claims a compression format that the contents don't
+// match is rejected.
+//
+// if 'fname' itself does not exist, but a compressed sibling
+// i.e. 'fname.bz2' or 'fname.gz' does, then that is opened instead.
+// this allows data files to be shipped compressed, while still being
+// referred to by their plain names.
+//
+// callers must Close() the returned reader when done.
+//
+func OpenFile(fname string) (rc io.ReadCloser, err error) {
+	var f *os.File
+
+	if f, err = os.Open(fname); os.IsNotExist(err) {
+		for _, ext := range []string{".bz2", ".gz"} {
+			if cf, cerr := os.Open(fname + ext); cerr == nil {
+				f, err, fname = cf, nil, fname+ext
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(10)
+	ext := filepath.Ext(fname)
+
+	switch {
+	case is_bzip2(magic):
+		return &compressed_file_t{bzip2.NewReader(br), []io.Closer{f}}, nil
+
+	case bytes.HasPrefix(magic, gzip_magic):
+		var zr *gzip.Reader
+
+		if zr, err = gzip.NewReader(br); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %s", fname, err)
+		}
+
+		return &compressed_file_t{zr, []io.Closer{zr, f}}, nil
+
+	case ext == ".bz2" || ext == ".gz":
+		f.Close()
+		return nil, fmt.Errorf("%s: contents do not match the '%s' compression format", fname, ext)
+	}
+
+	return &compressed_file_t{br, []io.Closer{f}}, nil
+}
+
+// does 'magic' look like the start of a bzip2 stream
+func is_bzip2(magic []byte) bool {
+	if len(magic) < 10 || !bytes.HasPrefix(magic, bzip2_magic) || magic[3] < '1' || magic[3] > '9' {
+		return false
+	}
+
+	return bytes.Equal(magic[4:], bzip2_block) || bytes.Equal(magic[4:], bzip2_stream_end)
+}
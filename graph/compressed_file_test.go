@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//
+// shipped .bz2 fixtures must load directly, and also when referred
+// to by their plain names
+//
+func TestLoadCompressedFixtures(t *testing.T) {
+	for _, fname := range []string{
+		"data/graph-001.data",
+		"data/graph-002.data",
+		"data/graph-003.data",
+		"data/graph-004.data",
+	} {
+		g1, err1 := LoadFromFile(fname + ".bz2")
+		g2, err2 := LoadFromFile(fname)
+
+		if err1 != nil || err2 != nil {
+			t.Logf("file: %s, unexpected errors: %v, %v\n", fname, err1, err2)
+			t.Fail()
+			continue
+		}
+
+		if g1.V() == 0 || g1.E() == 0 || !cmp_graph(g1, g2) {
+			t.Logf("file: %s, unexpected graph:\n%s\n", fname, g1)
+			t.Fail()
+		}
+	}
+
+	d, err := LoadDigraphFromFile("data/graph-002.data.bz2")
+	if err != nil || d.V() != 3 || d.E() != 3 {
+		t.Logf("unexpected digraph: %v, error: %v\n", d, err)
+		t.Fail()
+	}
+}
+
+//
+// gzip compressed files are detected by their contents, irrespective
+// of their names, while a mismatching extension is an error
+//
+func TestLoadGzipFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph-gzip")
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	g1 := create_bench_graph(64)
+
+	zname := filepath.Join(dir, "ring.data")
+	f, _ := os.Create(zname)
+	zw := gzip.NewWriter(f)
+	g1.WriteTo(zw)
+	zw.Close()
+	f.Close()
+
+	g2, err := LoadFromFile(zname)
+	if err != nil || !cmp_graph(g1, g2) {
+		t.Logf("gzip round-trip failed, error: %v\n", err)
+		t.Fail()
+	}
+
+	bogus := filepath.Join(dir, "bogus.data.gz")
+	ioutil.WriteFile(bogus, []byte("1\n0\n"), 0644)
+
+	if _, err = LoadFromFile(bogus); err == nil {
+		t.Logf("expected an error for a plain file named '%s'\n", bogus)
+		t.Fail()
+	}
+
+	if _, err = LoadFromFile(filepath.Join(dir, "missing.data")); !os.IsNotExist(err) {
+		t.Logf("expected a not-exist error, got: %v\n", err)
+		t.Fail()
+	}
+}
+
+//
+// plain text which happens to start with "BZh" mustn't be taken for
+// bzip2 compressed data
+//
+func TestOpenFileBzip2Lookalikes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph-bzh")
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	for i, data := range []string{
+		"BZh",
+		"BZh1",
+		"BZhang JFK\nBZhang ORD\n",
+		"BZh9 1AY&SX more text\n",
+	} {
+		fname := filepath.Join(dir, fmt.Sprintf("plain-%d.txt", i))
+		ioutil.WriteFile(fname, []byte(data), 0644)
+
+		rc, err := OpenFile(fname)
+		if err != nil {
+			t.Logf("%q: unexpected error: %s\n", data, err)
+			t.Fail()
+			continue
+		}
+
+		got, err := ioutil.ReadAll(rc)
+		rc.Close()
+
+		if err != nil || string(got) != data {
+			t.Logf("%q: got: %q, error: %v\n", data, got, err)
+			t.Fail()
+		}
+	}
+}
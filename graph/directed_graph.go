@@ -30,9 +30,8 @@ package graph
 
 import (
 	"bufio"
-	"fmt"
 	"io"
-	"os"
+	"strings"
 )
 
 //
@@ -41,9 +40,12 @@ import (
 // of processing
 //
 type Digraph struct {
-	v   int32
-	e   int32
-	adj []vertex_list_t
+	v      int32
+	e      int32
+	adj    []vertex_list_t
+	attrs  *Attributes // nil, until attributes are used
+	policy EdgePolicy  // edges rejected by AddEdge(...)
+	grow   bool        // AddEdge(...) adds missing vertices
 }
 
 //
@@ -63,9 +65,27 @@ func (G *Digraph) E() int32            { return G.e }
 func (G *Digraph) Adj(v int32) []int32 { return G.adj[v] }
 
 //
-// in a digraph G, add an edge between vertices 'v' and 'w'.
+// in a digraph G, add an edge between vertices 'v' and 'w'. edges not
+// allowed by the digraph's edge policy are rejected with
+// ErrParallelEdge or ErrSelfLoop. in auto-grow mode, vertices >= V
+// are added once the edge is accepted, otherwise they are rejected
+// with ErrBadVertex.
 //
-func (G *Digraph) AddEdge(v, w int32) {
+func (G *Digraph) AddEdge(v, w int32) (err error) {
+	var bound int32
+
+	if bound, err = edge_bound(G.v, v, w, G.grow); err != nil {
+		return
+	}
+
+	if G.policy != 0 {
+		if err = G.policy.check(G, v, w, true); err != nil {
+			return
+		}
+	}
+
+	G.grow_to(bound)
+
 	V := &G.adj[v]
 	*V = append(*V, w)
 
@@ -73,9 +93,125 @@ func (G *Digraph) AddEdge(v, w int32) {
 	return
 }
 
+//
+// set the kinds of edges rejected by subsequent AddEdge(...)
+// invokations. edges already in the digraph aren't affected.
+//
+func (G *Digraph) SetEdgePolicy(policy EdgePolicy) { G.policy = policy }
+func (G *Digraph) EdgePolicy() EdgePolicy          { return G.policy }
+
+//
+// in auto-grow mode, AddEdge(...) adds vertices up to the larger of
+// its endpoints (if required), so that the digraph can be built without
+// knowing the number of vertices up front.
+//
+func (G *Digraph) SetAutoGrow(yesno bool) { G.grow = yesno }
+func (G *Digraph) AutoGrow() bool         { return G.grow }
+
+//
+// is there an edge from vertex 'v' to 'w', and if so how many. out
+// of range vertices have no edges.
+//
+func (G *Digraph) HasEdge(v, w int32) bool       { return has_edge(G, v, w, true) }
+func (G *Digraph) Multiplicity(v, w int32) int32 { return multiplicity(G, v, w, true) }
+
+//
+// add a new, isolated vertex to the digraph, and return its id
+// i.e. the old vertex count
+//
+func (G *Digraph) AddVertex() (v int32) {
+	v = G.v
+
+	G.adj = append(G.adj, nil)
+	G.v += 1
+
+	return
+}
+
+// add vertices, so that there are at least 'V' of them
+func (G *Digraph) grow_to(V int32) {
+	if V > G.v {
+		G.adj = append(G.adj, make([]vertex_list_t, V-G.v)...)
+		G.v = V
+	}
+}
+
+//
+// remove one edge from 'v' to 'w' (there could be more than one), and
+// return true if there was such an edge.
+//
+func (G *Digraph) RemoveEdge(v, w int32) bool {
+	if !in_range(G.v, v, w) || !G.adj[v].remove(w) {
+		return false
+	}
+	G.attrs.edge_removed(v, w, G.adj[v])
+
+	G.e -= 1
+	return true
+}
+
+//
+// remove all the edges from vertex 'v' to 'w', and return their
+// number. there aren't any when either vertex is out of range.
+//
+func (G *Digraph) RemoveEdges(v, w int32) (n int32) {
+	if !in_range(G.v, v, w) {
+		return
+	}
+
+	if n = G.adj[v].remove_all(w); n > 0 {
+		G.attrs.edge_removed(v, w, G.adj[v])
+		G.e -= n
+	}
+
+	return
+}
+
+//
+// remove all the outgoing, and incoming edges of vertex 'v', leaving
+// it isolated, and return their number. finding the incoming edges
+// requires a scan of the entire digraph. out of range vertices are
+// rejected with ErrBadVertex.
+//
+func (G *Digraph) ClearVertex(v int32) (n int32, err error) {
+	if err = check_vertex(G.v, v); err != nil {
+		return
+	}
+
+	for len(G.adj[v]) > 0 {
+		n += G.RemoveEdges(v, G.adj[v][0])
+	}
+
+	for u := int32(0); u < G.v; u++ {
+		n += G.RemoveEdges(u, v)
+	}
+
+	return
+}
+
+//
+// remove vertex 'v' along with all its edges, and return the number
+// of edges removed. vertices after 'v' are renumbered down by one,
+// and their attributes (if any) go along with them. out of range
+// vertices are rejected with ErrBadVertex.
+//
+func (G *Digraph) RemoveVertex(v int32) (n int32, err error) {
+	var old_to_new []int32
+
+	if n, err = G.ClearVertex(v); err != nil {
+		return
+	}
+	G.adj, old_to_new = remove_isolated_vertex(G.adj, v)
+	G.attrs = G.attrs.remap(old_to_new)
+	G.v -= 1
+
+	return
+}
+
 //
 // return the reverse of a digraph i.e. adjacency list of each vertex
-// is reversed
+// is reversed. attributes (if any) go along with the vertices and
+// (reversed) edges.
 //
 func (G *Digraph) Reverse() (RevG *Digraph) {
 	RevG = CreateDigraph(G.V())
@@ -84,6 +220,7 @@ func (G *Digraph) Reverse() (RevG *Digraph) {
 			RevG.AddEdge(w, v)
 		}
 	}
+	RevG.attrs = G.attrs.reverse()
 
 	return
 }
@@ -96,51 +233,33 @@ func (G *Digraph) String() string { return graph_stringifier(G) }
 // subsequent loading from LoadFromXXX(...) invokation
 //
 func (G *Digraph) Serialize() string {
-	str := ""
+	var sb strings.Builder
 
-	// vertex and edge count
-	str += fmt.Sprintf("%d\n", G.V())
-	str += fmt.Sprintf("%d\n", G.E())
+	G.WriteTo(&sb)
+	return sb.String()
+}
 
-	// vertex-specific adjacency-list dump
-	for v := int32(0); v < G.V(); v++ {
-		for _, w := range G.Adj(v) {
-			str += fmt.Sprintf("%d %d\n", v, w)
-		}
-	}
+//
+// streaming version of Serialize(...), which implements io.WriterTo
+//
+func (G *Digraph) WriteTo(w io.Writer) (n int64, err error) {
+	return write_edge_list(w, G, true)
+}
 
-	return str
+//
+// streaming version of String(...)
+//
+func (G *Digraph) DumpTo(w io.Writer) (n int64, err error) {
+	return write_adjacency(w, G)
 }
 
 //
 // this function is called to create a graph from it's serialized
-// definition.
+// definition. malformed, or out-of-range edges are skipped, use
+// ParseDigraph(...) for the details, or for strict parsing.
 //
 func LoadDigraphFromReader(src *bufio.Reader) (new_graph *Digraph, err error) {
-	var V, E int32
-	var edges [][2]int32
-
-	V, E, edges, err = parse_graph_datafile(src)
-	if err != nil && err != io.EOF {
-		goto all_done
-	}
-
-	// create the graph, and setup the connections
-	new_graph = CreateDigraph(V)
-	for i := int32(0); i < E; i++ {
-		v, w := edges[i][0], edges[i][1]
-
-		// skip edges which are obviouzly bogus
-		if V < v || v < 0 || V < w || w < 0 {
-			fmt.Printf("skipping bogus connection: %d %d\n", v, w)
-			continue
-		}
-		new_graph.AddEdge(v, w)
-	}
-
-	ReverseAdjList(new_graph)
-
-all_done:
+	new_graph, _, err = ParseDigraph(src, ParseLenient)
 	return
 }
 
@@ -150,9 +269,9 @@ all_done:
 // 'fname'
 //
 func LoadDigraphFromFile(fname string) (g *Digraph, err error) {
-	var f *os.File
+	var f io.ReadCloser
 
-	if f, err = os.Open(fname); err != nil {
+	if f, err = OpenFile(fname); err != nil {
 		return nil, err
 	}
 	defer f.Close()
@@ -0,0 +1,200 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements emitting graphs and digraphs in graphviz's
+// dot language
+//
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//
+// controls how a graph is rendered in the dot language. all fields
+// are optional.
+//
+type DotOptions struct {
+	Name       string             // name of the graph
+	Highlight  []int32            // path to be highlighted
+	Component  func(int32) int32  // vertex -> component, for coloring
+	VertexName func(int32) string // vertex -> name, used as node id
+}
+
+// fill colors for components, reused cyclically
+var dot_component_colors = [...]string{
+	"lightblue", "lightgreen", "lightpink", "lightyellow", "lightsalmon",
+	"lightcyan", "plum", "wheat", "palegreen", "lightsteelblue",
+}
+
+// attributes of highlighted vertices and edges
+const dot_highlight_attrs = "color=red, penwidth=2"
+
+//
+// this function emits the graph structure in graphviz's dot
+// language. each edge of the undirected graph is emitted just once.
+// vertex and edge attributes are not emitted.
+//
+func (G *Graph) WriteDot(w io.Writer, opts *DotOptions) error {
+	return write_dot(w, G, false, opts)
+}
+
+//
+// this function emits the digraph structure in graphviz's dot
+// language. vertex and edge attributes are not emitted.
+//
+func (G *Digraph) WriteDot(w io.Writer, opts *DotOptions) error {
+	return write_dot(w, G, true, opts)
+}
+
+//
+// private unexported stuff
+//
+
+//
+// quote a string as a dot identifier. names made up of just letters,
+// digits and '_' (and not starting with a digit) are left as is.
+//
+func dot_quote(s string) string {
+	plain := len(s) > 0
+
+	for i, c := range s {
+		is_alpha := c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+		is_digit := '0' <= c && c <= '9'
+
+		if !is_alpha && !(is_digit && i > 0) {
+			plain = false
+			break
+		}
+	}
+
+	if plain && !is_dot_keyword(s) {
+		return s
+	}
+
+	return "\"" + dot_escaper.Replace(s) + "\""
+}
+
+// backslashes are escaped too, lest a trailing one escapes the closing quote
+var dot_escaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"")
+
+// dot keywords can't be used as plain identifiers
+func is_dot_keyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "graph", "digraph", "subgraph", "node", "edge", "strict":
+		return true
+	}
+
+	return false
+}
+
+//
+// emit 'G' in dot language. vertices are emitted first (so that
+// isolated vertices are retained), followed by the edges.
+//
+func write_dot(dst io.Writer, G GraphOps, directed bool, opts *DotOptions) error {
+	if opts == nil {
+		opts = &DotOptions{}
+	}
+
+	w := bufio.NewWriter(dst)
+	kind, edge_op := "graph", "--"
+	if directed {
+		kind, edge_op = "digraph", "->"
+	}
+
+	node_id := func(v int32) string { return strconv.Itoa(int(v)) }
+	if opts.VertexName != nil {
+		node_id = func(v int32) string { return dot_quote(opts.VertexName(v)) }
+	}
+
+	// vertices and edges on the highlighted path
+	on_path := make(map[int32]bool)
+	path_edges := make(map[[2]int32]bool)
+	for i, v := range opts.Highlight {
+		on_path[v] = true
+
+		if i > 0 {
+			u := opts.Highlight[i-1]
+			path_edges[[2]int32{u, v}] = true
+			if !directed {
+				path_edges[[2]int32{v, u}] = true
+			}
+		}
+	}
+
+	if len(opts.Name) > 0 {
+		fmt.Fprintf(w, "%s %s {\n", kind, dot_quote(opts.Name))
+	} else {
+		fmt.Fprintf(w, "%s {\n", kind)
+	}
+
+	// vertices
+	for v := int32(0); v < G.V(); v++ {
+		var attrs []string
+
+		if opts.Component != nil {
+			c := opts.Component(v)
+			color := dot_component_colors[int(c)%len(dot_component_colors)]
+			attrs = append(attrs, "style=filled", "fillcolor="+color)
+		}
+
+		if on_path[v] {
+			attrs = append(attrs, dot_highlight_attrs)
+		}
+
+		fmt.Fprintf(w, "\t%s%s;\n", node_id(v), dot_attr_list(attrs))
+	}
+
+	// edges
+	for_each_edge(G, directed, func(v, x int32) {
+		var attrs []string
+		if path_edges[[2]int32{v, x}] {
+			attrs = append(attrs, dot_highlight_attrs)
+		}
+
+		fmt.Fprintf(w, "\t%s %s %s%s;\n", node_id(v), edge_op, node_id(x), dot_attr_list(attrs))
+	})
+
+	fmt.Fprintf(w, "}\n")
+
+	return w.Flush()
+}
+
+// stringified attribute list, or nothing if there aren't any
+func dot_attr_list(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
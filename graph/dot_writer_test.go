@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleGraph_WriteDot() {
+	g := New(4)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 2)
+
+	component := func(v int32) int32 {
+		if v == 3 {
+			return 1
+		}
+		return 0
+	}
+
+	g.WriteDot(os.Stdout, &DotOptions{
+		Name:      "tiny",
+		Highlight: []int32{0, 1},
+		Component: component,
+	})
+
+	// Output:
+	// graph tiny {
+	// 	0 [style=filled, fillcolor=lightblue, color=red, penwidth=2];
+	// 	1 [style=filled, fillcolor=lightblue, color=red, penwidth=2];
+	// 	2 [style=filled, fillcolor=lightblue];
+	// 	3 [style=filled, fillcolor=lightgreen];
+	// 	0 -- 1 [color=red, penwidth=2];
+	// 	1 -- 2;
+	// 	2 -- 2;
+	// }
+}
+
+func ExampleDigraph_WriteDot() {
+	g := CreateDigraph(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 0)
+	g.AddEdge(1, 2)
+
+	names := []string{"a", "b c", "say \"hi\""}
+	g.WriteDot(os.Stdout, &DotOptions{
+		VertexName: func(v int32) string { return names[v] },
+	})
+
+	// Output:
+	// digraph {
+	// 	a;
+	// 	"b c";
+	// 	"say \"hi\"";
+	// 	a -> "b c";
+	// 	"b c" -> a;
+	// 	"b c" -> "say \"hi\"";
+	// }
+}
+
+//
+// every edge must be emitted exactly once, so number of edge
+// statements must match edge count of the graph
+//
+func TestWriteDotEdgeCount(t *testing.T) {
+	for i, graph := range graphs {
+		G := create_test_graph(&graph.graph_defn)
+
+		var buf bytes.Buffer
+		if err := G.WriteDot(&buf, nil); err != nil {
+			t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+			t.Fail()
+			continue
+		}
+
+		if n := int32(strings.Count(buf.String(), " -- ")); n != G.E() {
+			t.Logf("test-graph: %d, expected-edges: %d, found-edges: %d\n", i, G.E(), n)
+			t.Fail()
+		}
+	}
+}
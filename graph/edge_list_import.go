@@ -0,0 +1,422 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements importers for edge lists in the matrix market
+// (.mtx, as used by the suitesparse collection), and snap formats.
+// neither format carries the number of vertices in a form which is
+// directly usable here, so it is inferred from the vertex ids. sparse
+// ids can optionally be remapped into the dense [0, V) range needed
+// by GraphOps.
+//
+// when importing into an undirected graph, each unordered pair of
+// vertices results in (at most) one edge, irrespective of whether the
+// input lists one, or both directions.
+//
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//
+// mapping between vertex ids in an imported file, and the dense
+// vertex ids of the resulting graph. dense ids are handed out in the
+// order in which the original ids first appear in the input.
+//
+type IdMap struct {
+	dense    map[int64]int32
+	original []int64
+}
+
+// number of distinct ids seen
+func (m *IdMap) V() int32 { return int32(len(m.original)) }
+
+// dense vertex id of the original id 'id'
+func (m *IdMap) Dense(id int64) (v int32, ok bool) {
+	v, ok = m.dense[id]
+	return
+}
+
+// original id of the dense vertex id 'v'
+func (m *IdMap) Original(v int32) int64 { return m.original[v] }
+
+//
+// load an undirected graph from a snap edge list. each non-comment
+// line holds a pair of (non-negative) vertex ids, separated by
+// whitespace. when 'remap' is false, ids are used as they are and V is
+// one more than the largest id, while the returned IdMap is nil.
+//
+// unlike the other loaders, repeated edges are dropped: snap files
+// often list an undirected edge in both directions, so 'x y', 'y x'
+// and any repeats of them make a single edge x-y. self-loops are
+// kept, once.
+//
+func LoadFromSNAP(src io.Reader, remap bool) (G *Graph, ids *IdMap, err error) {
+	var imp *imported_edges_t
+
+	if imp, err = parse_snap(src, remap); err == nil {
+		G, ids = imp.graph(), imp.ids
+	}
+
+	return
+}
+
+// digraph version of LoadFromSNAP(...), each line is a 'from to' arc
+func LoadDigraphFromSNAP(src io.Reader, remap bool) (G *Digraph, ids *IdMap, err error) {
+	var imp *imported_edges_t
+
+	if imp, err = parse_snap(src, remap); err == nil {
+		G, ids = imp.digraph(), imp.ids
+	}
+
+	return
+}
+
+//
+// load an undirected graph from a matrix market file in the
+// 'coordinate' format. every stored entry (i, j) is an edge between
+// vertices i-1 and j-1, values (if any) are ignored. when 'remap' is
+// false, V is the larger of the matrix dimensions, and the returned
+// IdMap is nil. otherwise, only the vertices which appear in some
+// entry are kept, and their original (1-based) indices are reported.
+//
+// unlike the other loaders, repeated edges are dropped: entries (i, j)
+// and (j, i), and any repeats of them make a single edge, so that
+// general (non-symmetric) matrices storing both triangles don't
+// result in parallel edges. diagonal entries make self-loops, once.
+//
+func LoadFromMatrixMarket(src io.Reader, remap bool) (G *Graph, ids *IdMap, err error) {
+	var imp *imported_edges_t
+
+	if imp, err = parse_matrix_market(src, remap); err == nil {
+		G, ids = imp.graph(), imp.ids
+	}
+
+	return
+}
+
+//
+// digraph version of LoadFromMatrixMarket(...). entry (i, j) is the
+// arc i-1 -> j-1, and for symmetric matrices, the arc j-1 -> i-1 is
+// added as well.
+//
+func LoadDigraphFromMatrixMarket(src io.Reader, remap bool) (G *Digraph, ids *IdMap, err error) {
+	var imp *imported_edges_t
+
+	if imp, err = parse_matrix_market(src, remap); err == nil {
+		G, ids = imp.digraph(), imp.ids
+	}
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// edges gathered by an importer, before the graph is built
+type imported_edges_t struct {
+	v         int32
+	edges     [][2]int32
+	symmetric bool   // each edge stands for both directions
+	ids       *IdMap // nil, unless remapping
+}
+
+// record an edge between the original ids 'x' and 'y'
+func (imp *imported_edges_t) add(x, y int64) {
+	var v, w int32
+
+	if imp.ids != nil {
+		v, w = imp.ids.add(x), imp.ids.add(y)
+		imp.v = imp.ids.V()
+	} else {
+		v, w = int32(x), int32(y)
+	}
+
+	imp.edges = append(imp.edges, [2]int32{v, w})
+}
+
+//
+// undirected graph from the imported edges, with at most one edge per
+// unordered pair of vertices
+//
+func (imp *imported_edges_t) graph() (G *Graph) {
+	seen := make(map[[2]int32]bool, len(imp.edges))
+
+	G = New(imp.v)
+	for _, e := range imp.edges {
+		v, w := e[0], e[1]
+		if v > w {
+			v, w = w, v
+		}
+
+		if !seen[[2]int32{v, w}] {
+			seen[[2]int32{v, w}] = true
+			G.AddEdge(e[0], e[1])
+		}
+	}
+
+	return
+}
+
+func (imp *imported_edges_t) digraph() (G *Digraph) {
+	G = CreateDigraph(imp.v)
+	for _, e := range imp.edges {
+		G.AddEdge(e[0], e[1])
+		if imp.symmetric && e[0] != e[1] {
+			G.AddEdge(e[1], e[0])
+		}
+	}
+
+	return
+}
+
+func (m *IdMap) add(id int64) (v int32) {
+	v, ok := m.dense[id]
+	if !ok {
+		v = int32(len(m.original))
+		m.dense[id] = v
+		m.original = append(m.original, id)
+	}
+
+	return
+}
+
+func new_imported_edges(remap bool) *imported_edges_t {
+	imp := &imported_edges_t{}
+	if remap {
+		imp.ids = &IdMap{dense: make(map[int64]int32)}
+	}
+
+	return imp
+}
+
+// parse a snap edge list
+func parse_snap(src io.Reader, remap bool) (imp *imported_edges_t, err error) {
+	var text string
+	var ok bool
+
+	p := &graph_parser_t{src: bufio.NewReader(src), mode: ParseStrict, comment: comment_char}
+	imp = new_imported_edges(remap)
+
+	max_id := int64(-1)
+	for {
+		if text, ok, err = p.next_line(); err != nil || !ok {
+			break
+		}
+
+		tokens := strings.Fields(text)
+		if len(tokens) != 2 {
+			return nil, p.complain(text, "expected two vertex ids")
+		}
+
+		var ids [2]int64
+		for i := range ids {
+			if ids[i], err = p.parse_id(text, tokens[i], remap); err != nil {
+				return nil, err
+			}
+
+			if ids[i] > max_id {
+				max_id = ids[i]
+			}
+		}
+
+		imp.add(ids[0], ids[1])
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !remap {
+		imp.v = int32(max_id + 1)
+	}
+
+	return
+}
+
+//
+// parse a matrix market file in the coordinate format, which looks
+// like the following
+//
+//     %%MatrixMarket matrix coordinate <field> <symmetry>
+//     % comments
+//     rows columns entries
+//     i j [value...]
+//     ....................
+//
+// indices start at 1
+//
+func parse_matrix_market(src io.Reader, remap bool) (imp *imported_edges_t, err error) {
+	var text string
+	var ok bool
+
+	p := &graph_parser_t{src: bufio.NewReader(src), mode: ParseStrict, comment: '%'}
+	imp = new_imported_edges(remap)
+
+	// banner
+	if text, err = p.src.ReadString('\n'); err != nil && err != io.EOF {
+		return nil, err
+	}
+	p.line_no++
+
+	if imp.symmetric, err = p.parse_mm_banner(strings.TrimSpace(text)); err != nil {
+		return nil, err
+	}
+
+	// dimensions
+	if text, ok, err = p.next_line(); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &ParseError{Line: p.line_no, Reason: "missing matrix dimensions"}
+	}
+
+	var dims [3]int64
+	tokens := strings.Fields(text)
+	if len(tokens) != 3 {
+		return nil, p.complain(text, "expected 'rows columns entries'")
+	}
+
+	for i := range dims {
+		if dims[i], err = strconv.ParseInt(tokens[i], 10, 64); err != nil || dims[i] < 0 {
+			return nil, p.complain(text, fmt.Sprintf("malformed dimension %q", tokens[i]))
+		}
+	}
+
+	rows, cols, entries := dims[0], dims[1], dims[2]
+	if rows > 1<<31-1 || cols > 1<<31-1 {
+		return nil, p.complain(text, "matrix is too large")
+	}
+
+	// entries
+	for n := int64(0); ; n++ {
+		if text, ok, err = p.next_line(); err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			if n != entries {
+				return nil, &ParseError{Reason: fmt.Sprintf("expected %d entries, found %d", entries, n)}
+			}
+			break
+		}
+
+		if n == entries {
+			return nil, p.complain(text, fmt.Sprintf("more than %d entries", entries))
+		}
+
+		tokens := strings.Fields(text)
+		if len(tokens) < 2 {
+			return nil, p.complain(text, "expected row and column indices")
+		}
+
+		var ij [2]int64
+		for i, limit := range [2]int64{rows, cols} {
+			ij[i], err = strconv.ParseInt(tokens[i], 10, 64)
+			if err != nil {
+				return nil, p.complain(text, fmt.Sprintf("non-integer token %q", tokens[i]))
+			}
+
+			if ij[i] < 1 || ij[i] > limit {
+				return nil, p.complain(text, fmt.Sprintf("index %d out of range [1, %d]", ij[i], limit))
+			}
+		}
+
+		if remap {
+			imp.add(ij[0], ij[1])
+		} else {
+			imp.add(ij[0]-1, ij[1]-1)
+		}
+	}
+
+	if !remap {
+		imp.v = int32(rows)
+		if cols > rows {
+			imp.v = int32(cols)
+		}
+	}
+
+	return
+}
+
+//
+// check the matrix market banner, and return whether the matrix is
+// symmetric
+//
+func (p *graph_parser_t) parse_mm_banner(text string) (symmetric bool, err error) {
+	tokens := strings.Fields(strings.ToLower(text))
+
+	if len(tokens) != 5 || tokens[0] != "%%matrixmarket" || tokens[1] != "matrix" {
+		return false, p.complain(text, "not a matrix market banner")
+	}
+
+	if tokens[2] != "coordinate" {
+		return false, p.complain(text, fmt.Sprintf("unsupported format %q, expected \"coordinate\"", tokens[2]))
+	}
+
+	switch tokens[3] {
+	case "pattern", "real", "integer", "complex":
+	default:
+		return false, p.complain(text, fmt.Sprintf("unknown field %q", tokens[3]))
+	}
+
+	switch tokens[4] {
+	case "general":
+		return false, nil
+
+	case "symmetric", "skew-symmetric", "hermitian":
+		return true, nil
+	}
+
+	return false, p.complain(text, fmt.Sprintf("unknown symmetry %q", tokens[4]))
+}
+
+//
+// parse a vertex id from a snap edge list. without remapping, ids
+// must be usable as vertices as they are
+//
+func (p *graph_parser_t) parse_id(text, token string, remap bool) (id int64, err error) {
+	if id, err = strconv.ParseInt(token, 10, 64); err != nil {
+		return 0, p.complain(text, fmt.Sprintf("non-integer token %q", token))
+	}
+
+	if id < 0 {
+		return 0, p.complain(text, fmt.Sprintf("negative vertex id %d", id))
+	}
+
+	if !remap && id >= 1<<31-1 {
+		return 0, p.complain(text, fmt.Sprintf("vertex id %d is too large without remapping", id))
+	}
+
+	return id, nil
+}
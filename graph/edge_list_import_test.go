@@ -0,0 +1,202 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleLoadFromSNAP() {
+	data := "# Directed graph: example.txt\n# FromNodeId\tToNodeId\n" +
+		"1000\t20\n20\t1000\n20\t7\n7\t1000\n"
+
+	g, ids, _ := LoadFromSNAP(strings.NewReader(data), true)
+
+	fmt.Println(g.V(), g.E())
+	for v := int32(0); v < g.V(); v++ {
+		fmt.Println(v, ids.Original(v), g.Adj(v))
+	}
+
+	// Output:
+	// 3 3
+	// 0 1000 [1 2]
+	// 1 20 [0 2]
+	// 2 7 [1 0]
+}
+
+func TestLoadFromSNAP(t *testing.T) {
+	data := "# comment\n0 3\n3\t0\n\n2 2\n3 1 # trailing comment\n"
+
+	d, ids, err := LoadDigraphFromSNAP(strings.NewReader(data), false)
+	if err != nil || ids != nil {
+		t.Logf("unexpected error: %v, id-map: %v\n", err, ids)
+		t.FailNow()
+	}
+
+	want := create_test_digraph_from(4, [][2]int32{{0, 3}, {3, 0}, {2, 2}, {3, 1}})
+	if d.String() != want.String() {
+		t.Logf("expected:\n%s\ngot:\n%s\n", want, d)
+		t.Fail()
+	}
+
+	g, _, _ := LoadFromSNAP(strings.NewReader(data), false)
+	if g.V() != 4 || g.E() != 3 {
+		t.Logf("unexpected graph:\n%s\n", g)
+		t.Fail()
+	}
+
+	test_cases := []struct {
+		data   string
+		remap  bool
+		reason string
+	}{
+		{"0 1 2\n", false, "expected two vertex ids"},
+		{"0 x\n", false, "non-integer token"},
+		{"0 -1\n", true, "negative vertex id"},
+		{"0 4294967296\n", false, "too large without remapping"},
+	}
+
+	for i, tc := range test_cases {
+		_, _, err := LoadFromSNAP(strings.NewReader(tc.data), tc.remap)
+		if pe, ok := err.(*ParseError); !ok || pe.Line != 1 || !strings.Contains(pe.Reason, tc.reason) {
+			t.Logf("test-case: %d, expected: %s, got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+
+	// large ids are fine once remapped
+	if g, ids, err = LoadFromSNAP(strings.NewReader("0 4294967296\n"), true); err != nil || g.V() != 2 || ids.Original(1) != 4294967296 {
+		t.Logf("unexpected error: %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestLoadFromMatrixMarket(t *testing.T) {
+	data := "%%MatrixMarket matrix coordinate real symmetric\n" +
+		"% a 5x5 symmetric matrix, lower triangle only\n" +
+		"5 5 4\n" +
+		"2 1 1.5\n" +
+		"4 2 -2.0\n" +
+		"4 4 1.0\n" +
+		"5 2 3.0\n"
+
+	g, ids, err := LoadFromMatrixMarket(strings.NewReader(data), false)
+	if err != nil || ids != nil || g.V() != 5 || g.E() != 4 {
+		t.Logf("unexpected graph: %v, error: %v\n", g, err)
+		t.FailNow()
+	}
+
+	// symmetric entries stand for both arcs, except on the diagonal
+	d, _, _ := LoadDigraphFromMatrixMarket(strings.NewReader(data), false)
+	want := create_test_digraph_from(5, [][2]int32{{1, 0}, {0, 1}, {3, 1}, {1, 3}, {3, 3}, {4, 1}, {1, 4}})
+	if d.String() != want.String() {
+		t.Logf("expected:\n%s\ngot:\n%s\n", want, d)
+		t.Fail()
+	}
+
+	// vertex 3 (index 3) never appears, and is dropped when remapping
+	g, ids, err = LoadFromMatrixMarket(strings.NewReader(data), true)
+	if err != nil || g.V() != 4 || ids.Original(0) != 2 {
+		t.Logf("unexpected graph: %v, error: %v\n", g, err)
+		t.FailNow()
+	}
+
+	if v, ok := ids.Dense(5); !ok || v != 3 {
+		t.Logf("index 5, expected dense id: 3, got: %d\n", v)
+		t.Fail()
+	}
+
+	if _, ok := ids.Dense(3); ok {
+		t.Logf("index 3, expected no dense id\n")
+		t.Fail()
+	}
+
+	test_cases := []struct {
+		data   string
+		reason string
+	}{
+		{"", "not a matrix market banner"},
+		{"%%MatrixMarket matrix array real general\n", "unsupported format"},
+		{"%%MatrixMarket matrix coordinate real lower\n", "unknown symmetry"},
+		{"%%MatrixMarket matrix coordinate pattern general\n", "missing matrix dimensions"},
+		{"%%MatrixMarket matrix coordinate pattern general\n3 3\n", "expected 'rows columns entries'"},
+		{"%%MatrixMarket matrix coordinate pattern general\n3 3 1\n1 4\n", "index 4 out of range"},
+		{"%%MatrixMarket matrix coordinate pattern general\n3 3 1\n0 1\n", "index 0 out of range"},
+		{"%%MatrixMarket matrix coordinate pattern general\n3 3 2\n1 2\n", "expected 2 entries, found 1"},
+		{"%%MatrixMarket matrix coordinate pattern general\n3 3 1\n1 2\n2 3\n", "more than 1 entries"},
+	}
+
+	for i, tc := range test_cases {
+		_, _, err := LoadDigraphFromMatrixMarket(strings.NewReader(tc.data), false)
+		if pe, ok := err.(*ParseError); !ok || !strings.Contains(pe.Reason, tc.reason) {
+			t.Logf("test-case: %d, expected: %s, got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+}
+
+// digraph on 'V' vertices with the arcs in 'edges'
+func create_test_digraph_from(V int32, edges [][2]int32) *Digraph {
+	d := CreateDigraph(V)
+	for _, e := range edges {
+		d.AddEdge(e[0], e[1])
+	}
+
+	return d
+}
+
+//
+// undirected imports make at most one edge per unordered pair of
+// vertices, whichever way round, and however often it is listed.
+// digraph imports keep every arc.
+//
+func TestImportDropsRepeatedEdges(t *testing.T) {
+	snap := "0 1\n1 0\n0 1\n2 2\n2 2\n1 2\n"
+	mtx := "%%MatrixMarket matrix coordinate pattern general\n" +
+		"3 3 6\n1 2\n2 1\n1 2\n3 3\n3 3\n2 3\n"
+
+	want := create_test_graph(&graph_definition{3, 3, [][2]int32{{0, 1}, {2, 2}, {1, 2}}})
+
+	g1, _, err1 := LoadFromSNAP(strings.NewReader(snap), false)
+	g2, _, err2 := LoadFromMatrixMarket(strings.NewReader(mtx), false)
+
+	for i, g := range []*Graph{g1, g2} {
+		if err1 != nil || err2 != nil || g.String() != want.String() {
+			t.Logf("loader: %d, errors: %v, %v, expected:\n%s\ngot:\n%s\n", i, err1, err2, want, g)
+			t.Fail()
+		}
+	}
+
+	d1, _, _ := LoadDigraphFromSNAP(strings.NewReader(snap), false)
+	d2, _, _ := LoadDigraphFromMatrixMarket(strings.NewReader(mtx), false)
+
+	if d1.E() != 6 || d2.E() != 6 || d1.Multiplicity(0, 1) != 2 || d2.Multiplicity(2, 2) != 2 {
+		t.Logf("digraphs lost repeated arcs:\n%s\n%s\n", d1, d2)
+		t.Fail()
+	}
+}
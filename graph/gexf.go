@@ -0,0 +1,429 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements reading and writing graphs and digraphs in
+// the gexf format (as understood by gephi)
+//
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const gexf_xmlns = "http://gexf.net/1.3"
+
+//
+// gexf document structure, only the parts that we care about are
+// described here, everything else is ignored while reading
+//
+type gexf_doc_t struct {
+	XMLName xml.Name     `xml:"gexf"`
+	Xmlns   string       `xml:"xmlns,attr,omitempty"`
+	Version string       `xml:"version,attr"`
+	Graph   gexf_graph_t `xml:"graph"`
+}
+
+type gexf_graph_t struct {
+	Mode            string              `xml:"mode,attr,omitempty"`
+	DefaultEdgeType string              `xml:"defaultedgetype,attr"`
+	Attributes      []gexf_attributes_t `xml:"attributes"`
+	Nodes           []gexf_node_t       `xml:"nodes>node"`
+	Edges           []gexf_edge_t       `xml:"edges>edge"`
+}
+
+// attribute declarations for either nodes or edges
+type gexf_attributes_t struct {
+	Class      string             `xml:"class,attr"`
+	Attributes []gexf_attribute_t `xml:"attribute"`
+}
+
+type gexf_attribute_t struct {
+	Id    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexf_node_t struct {
+	Id        string            `xml:"id,attr"`
+	Label     string            `xml:"label,attr,omitempty"`
+	AttValues *gexf_attvalues_t `xml:"attvalues"`
+}
+
+type gexf_edge_t struct {
+	Id        string            `xml:"id,attr"`
+	Source    string            `xml:"source,attr"`
+	Target    string            `xml:"target,attr"`
+	AttValues *gexf_attvalues_t `xml:"attvalues"`
+}
+
+// attvalues are left out altogether for vertices and edges without them
+type gexf_attvalues_t struct {
+	Values []gexf_attvalue_t `xml:"attvalue"`
+}
+
+type gexf_attvalue_t struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+//
+// this function emits the graph structure in gexf format. when
+// 'labels' is non-nil, labels[v] is emitted as the label of vertex
+// 'v'. vertex and edge attributes (if any) are emitted as attvalues.
+//
+func (G *Graph) WriteGEXF(w io.Writer, labels []string) error {
+	return write_gexf(w, G, false, labels, G.attrs)
+}
+
+//
+// this function emits the digraph structure in gexf format. when
+// 'labels' is non-nil, labels[v] is emitted as the label of vertex
+// 'v'. vertex and edge attributes (if any) are emitted as attvalues.
+//
+func (G *Digraph) WriteGEXF(w io.Writer, labels []string) error {
+	return write_gexf(w, G, true, labels, G.attrs)
+}
+
+//
+// this function is called to create a graph from its gexf
+// definition. vertices are numbered in the order in which they are
+// declared. vertex labels are returned if the document has them, nil
+// otherwise. node and edge attvalues become their attributes.
+//
+func LoadFromGEXF(src io.Reader) (new_graph *Graph, labels []string, err error) {
+	var V int32
+	var edges [][2]int32
+	var attrs *Attributes
+
+	if V, edges, labels, attrs, err = parse_gexf(src, false); err != nil {
+		return
+	}
+
+	new_graph = New(V)
+	new_graph.attrs = attrs
+	for _, e := range edges {
+		new_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// this function is called to create a digraph from its gexf
+// definition. vertices are numbered in the order in which they are
+// declared. vertex labels are returned if the document has them, nil
+// otherwise. node and edge attvalues become their attributes.
+//
+func LoadDigraphFromGEXF(src io.Reader) (new_graph *Digraph, labels []string, err error) {
+	var V int32
+	var edges [][2]int32
+	var attrs *Attributes
+
+	if V, edges, labels, attrs, err = parse_gexf(src, true); err != nil {
+		return
+	}
+
+	new_graph = CreateDigraph(V)
+	new_graph.attrs = attrs
+	for _, e := range edges {
+		new_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// emit 'G' as a gexf document
+func write_gexf(w io.Writer, G GraphOps, directed bool, labels []string, attrs *Attributes) (err error) {
+	doc := gexf_doc_t{
+		Xmlns:   gexf_xmlns,
+		Version: "1.3",
+		Graph: gexf_graph_t{
+			Mode:            "static",
+			DefaultEdgeType: "undirected",
+			Nodes:           make([]gexf_node_t, G.V()),
+		},
+	}
+
+	if directed {
+		doc.Graph.DefaultEdgeType = "directed"
+	}
+
+	// attribute-name -> attribute-id, for vertices and edges
+	var vertex_ids, edge_ids map[string]string
+	if attrs != nil {
+		if vertex_ids, edge_ids, err = gexf_attr_decls(&doc, attrs); err != nil {
+			return fmt.Errorf("gexf: %s", err)
+		}
+	}
+
+	for v := int32(0); v < G.V(); v++ {
+		node := &doc.Graph.Nodes[v]
+		node.Id = strconv.Itoa(int(v))
+
+		if labels != nil {
+			node.Label = labels[v]
+		}
+		if attrs != nil {
+			node.AttValues = gexf_attvalues(attrs.VertexAttrs(v), vertex_ids)
+		}
+	}
+
+	for_each_edge(G, directed, func(v, w int32) {
+		edge := gexf_edge_t{
+			Id:     strconv.Itoa(len(doc.Graph.Edges)),
+			Source: strconv.Itoa(int(v)),
+			Target: strconv.Itoa(int(w)),
+		}
+		if attrs != nil {
+			edge.AttValues = gexf_attvalues(attrs.EdgeAttrs(v, w), edge_ids)
+		}
+
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	})
+
+	return write_xml_doc(w, &doc)
+}
+
+//
+// declare all the vertex, and edge attributes in use, and return the
+// attribute ids for each attribute name
+//
+func gexf_attr_decls(doc *gexf_doc_t, attrs *Attributes) (vertex_ids, edge_ids map[string]string, err error) {
+	var vertex, edge []AttrSchema
+
+	if vertex, edge, err = attrs.Schema(); err != nil {
+		return
+	}
+
+	declare := func(schema []AttrSchema, class string) map[string]string {
+		ids := make(map[string]string, len(schema))
+		if len(schema) == 0 {
+			return ids
+		}
+
+		decl := gexf_attributes_t{Class: class}
+		for i, s := range schema {
+			attr := gexf_attribute_t{Id: strconv.Itoa(i), Title: s.Name, Type: "string"}
+
+			switch s.Kind {
+			case AttrNumber:
+				attr.Type = "double"
+			case AttrTags:
+				attr.Type = "liststring"
+			}
+
+			decl.Attributes = append(decl.Attributes, attr)
+			ids[s.Name] = attr.Id
+		}
+
+		doc.Graph.Attributes = append(doc.Graph.Attributes, decl)
+		return ids
+	}
+
+	vertex_ids, edge_ids = declare(vertex, "node"), declare(edge, "edge")
+	return
+}
+
+//
+// attvalues for attributes of a vertex or an edge, sorted by
+// attribute id. tags are emitted as a json array of strings.
+//
+func gexf_attvalues(m AttrMap, ids map[string]string) *gexf_attvalues_t {
+	if len(m) == 0 {
+		return nil
+	}
+
+	values := make([]gexf_attvalue_t, 0, len(m))
+	for name, val := range m {
+		av := gexf_attvalue_t{For: ids[name], Value: val.String()}
+
+		if val.Kind == AttrTags {
+			doc, _ := json.Marshal(append(make([]string, 0, len(val.Tags)), val.Tags...))
+			av.Value = string(doc)
+		}
+
+		values = append(values, av)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].For < values[j].For })
+	return &gexf_attvalues_t{Values: values}
+}
+
+//
+// attribute value for a gexf attvalue. numeric types become numbers,
+// list types become tags, and everything else is taken as a
+// string. lists are either json arrays (as written by us) or '|'
+// separated (as in gexf 1.2).
+//
+func gexf_attr_value(attr *gexf_attribute_t, value string) (val Attr, err error) {
+	switch {
+	case strings.HasPrefix(attr.Type, "list"):
+		var tags []string
+		if strings.HasPrefix(value, "[") {
+			if err = json.Unmarshal([]byte(value), &tags); err != nil {
+				err = fmt.Errorf("bad list value for attribute '%s': %q", attr.Id, value)
+			}
+		} else if len(value) > 0 {
+			tags = strings.Split(value, "|")
+		}
+		val = TagsAttr(tags...)
+
+	case attr.Type == "integer" || attr.Type == "long" || attr.Type == "float" || attr.Type == "double":
+		var x float64
+		if x, err = strconv.ParseFloat(value, 64); err != nil {
+			err = fmt.Errorf("bad %s value for attribute '%s': %q", attr.Type, attr.Id, value)
+		}
+		val = NumberAttr(x)
+
+	default:
+		val = StringAttr(value)
+	}
+
+	return
+}
+
+//
+// parse a gexf document, ensuring that it describes the expected
+// kind of graph. gexf defaults to directed edges.
+//
+func parse_gexf(src io.Reader, directed bool) (V int32, edges [][2]int32, labels []string, attrs *Attributes, err error) {
+	var doc gexf_doc_t
+
+	if err = xml.NewDecoder(src).Decode(&doc); err != nil {
+		err = fmt.Errorf("gexf: %s", err)
+		return
+	}
+
+	edge_type := doc.Graph.DefaultEdgeType
+	if len(edge_type) == 0 {
+		edge_type = "directed"
+	}
+
+	if is_directed := edge_type == "directed"; is_directed != directed {
+		err = fmt.Errorf("gexf: unexpected defaultedgetype: '%s'", edge_type)
+		return
+	}
+
+	// attribute declarations, by class and id
+	decls := map[string]map[string]*gexf_attribute_t{"node": {}, "edge": {}}
+	for i := range doc.Graph.Attributes {
+		decl := &doc.Graph.Attributes[i]
+		if _, ok := decls[decl.Class]; !ok {
+			continue
+		}
+
+		for j := range decl.Attributes {
+			decls[decl.Class][decl.Attributes[j].Id] = &decl.Attributes[j]
+		}
+	}
+
+	// attributes of a vertex or an edge, from its attvalues
+	new_attrs := new_attributes(directed)
+	add_attrs := func(class string, values *gexf_attvalues_t, set func(string, Attr)) error {
+		if values == nil {
+			return nil
+		}
+
+		for _, av := range values.Values {
+			attr, ok := decls[class][av.For]
+			if !ok {
+				continue
+			}
+
+			val, err := gexf_attr_value(attr, av.Value)
+			if err != nil {
+				return err
+			}
+			set(attr.Title, val)
+		}
+		return nil
+	}
+
+	// node-id -> vertex
+	vertex_of := make(map[string]int32, len(doc.Graph.Nodes))
+	has_labels := false
+
+	for i, node := range doc.Graph.Nodes {
+		if _, dup := vertex_of[node.Id]; dup {
+			err = fmt.Errorf("gexf: duplicate node: '%s'", node.Id)
+			return
+		}
+		vertex_of[node.Id] = int32(i)
+		has_labels = has_labels || len(node.Label) > 0
+
+		v := int32(i)
+		err = add_attrs("node", node.AttValues, func(name string, val Attr) { new_attrs.SetVertex(v, name, val) })
+		if err != nil {
+			err = fmt.Errorf("gexf: node '%s': %s", node.Id, err)
+			return
+		}
+	}
+
+	if has_labels {
+		labels = make([]string, len(doc.Graph.Nodes))
+		for i, node := range doc.Graph.Nodes {
+			labels[i] = node.Label
+		}
+	}
+
+	V = int32(len(doc.Graph.Nodes))
+	edges = make([][2]int32, len(doc.Graph.Edges))
+
+	for i, e := range doc.Graph.Edges {
+		v, v_ok := vertex_of[e.Source]
+		w, w_ok := vertex_of[e.Target]
+
+		if !v_ok || !w_ok {
+			err = fmt.Errorf("gexf: edge %s -> %s refers to an undeclared node", e.Source, e.Target)
+			return
+		}
+		edges[i] = [2]int32{v, w}
+
+		err = add_attrs("edge", e.AttValues, func(name string, val Attr) { new_attrs.SetEdge(v, w, name, val) })
+		if err != nil {
+			err = fmt.Errorf("gexf: edge %s -> %s: %s", e.Source, e.Target, err)
+			return
+		}
+	}
+
+	if len(new_attrs.vertex) > 0 || len(new_attrs.edge) > 0 {
+		attrs = new_attrs
+	}
+
+	return
+}
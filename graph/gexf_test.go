@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+//
+// write graphs in gexf, read them back and compare the two for
+// equality.
+//
+func TestGEXFRoundTrip(t *testing.T) {
+	for i, graph := range graphs {
+		g1 := create_test_graph(&graph.graph_defn)
+
+		var buf bytes.Buffer
+		if err := g1.WriteGEXF(&buf, nil); err != nil {
+			t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+			t.FailNow()
+		}
+
+		g2, labels, err := LoadFromGEXF(&buf)
+		if err != nil || labels != nil || !cmp_graph(g1, g2) {
+			t.Logf("test-graph: %d, error: %v, labels: %v\n", i, err, labels)
+			t.Logf("original-graph:\n%s", g1)
+			t.Logf("new-graph:\n%s", g2)
+			t.Fail()
+		}
+	}
+
+	dg1 := create_xml_test_digraph()
+	names := []string{"a", "b", "<c>", "d & e", "f"}
+
+	var buf bytes.Buffer
+	dg1.WriteGEXF(&buf, names)
+
+	dg2, labels, err := LoadDigraphFromGEXF(&buf)
+	if err != nil || !cmp_graph(dg1, dg2) || strings.Join(labels, ",") != strings.Join(names, ",") {
+		t.Logf("digraph round-trip failed, error: %v, labels: %v\n", err, labels)
+		t.Logf("original-graph:\n%s", dg1)
+		t.Logf("new-graph:\n%s", dg2)
+		t.Fail()
+	}
+}
+
+//
+// gephi documents carry a lot more than what we read, and edges are
+// directed unless stated otherwise
+//
+func TestGEXFForeign(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <meta lastmodifieddate="2024-01-01"><creator>Gephi</creator></meta>
+  <graph mode="static">
+    <attributes class="node"><attribute id="0" title="kind" type="string"/></attributes>
+    <nodes>
+      <node id="x" label="Hello"/>
+      <node id="y" label="World"><attvalues><attvalue for="0" value="w"/></attvalues></node>
+    </nodes>
+    <edges>
+      <edge id="e0" source="x" target="y" weight="2.0"/>
+    </edges>
+  </graph>
+</gexf>`
+
+	g, labels, err := LoadDigraphFromGEXF(strings.NewReader(doc))
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if g.V() != 2 || g.E() != 1 || g.Adj(0)[0] != 1 || labels[0] != "Hello" || labels[1] != "World" {
+		t.Logf("bad graph: %s, labels: %v\n", g, labels)
+		t.Fail()
+	}
+
+	if _, _, err := LoadFromGEXF(strings.NewReader(doc)); err == nil {
+		t.Logf("expected error loading a digraph as graph\n")
+		t.Fail()
+	}
+}
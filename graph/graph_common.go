@@ -31,12 +31,223 @@ package graph
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
-	"github.com/anupamk/common-utilz/line_parser"
 	"github.com/anupamk/common-utilz/slice_utils"
+	"io"
+	"math"
 	"sort"
+	"strconv"
+	"strings"
 )
 
+//
+// what kinds of edges AddEdge(...) accepts. by default every edge is
+// accepted, making multigraphs with self-loops.
+//
+type EdgePolicy uint8
+
+const (
+	RejectParallelEdges EdgePolicy = 1 << iota // no more than one edge between two vertices
+	RejectSelfLoops                            // no edge v-v (or v -> v)
+
+	// both of the above i.e. a simple graph
+	SimpleGraph = RejectParallelEdges | RejectSelfLoops
+)
+
+var (
+	ErrParallelEdge = errors.New("parallel edge rejected")
+	ErrSelfLoop     = errors.New("self-loop rejected")
+	ErrBadVertex    = errors.New("bad vertex")
+)
+
+//
+// number of vertices required to have both 'v' and 'w' in range. ids
+// must be non-negative, and leave room for the count itself.
+//
+func vertex_bound(v, w int32) (V int32, err error) {
+	for _, x := range [...]int32{v, w} {
+		if x < 0 || x == math.MaxInt32 {
+			return 0, fmt.Errorf("%w: %d", ErrBadVertex, x)
+		}
+	}
+
+	if V = v + 1; w >= v {
+		V = w + 1
+	}
+
+	return
+}
+
+//
+// number of vertices a graph with 'n' vertices needs, for an edge
+// between 'v' and 'w'. vertices >= n are out of range unless 'grow' is
+// set.
+//
+func edge_bound(n, v, w int32, grow bool) (V int32, err error) {
+	if V, err = vertex_bound(v, w); err != nil {
+		return
+	}
+
+	if V > n && !grow {
+		return n, fmt.Errorf("%w: %d", ErrBadVertex, V-1)
+	}
+
+	if V < n {
+		V = n
+	}
+
+	return
+}
+
+//
+// check a new edge v-w (or v -> w) against the edge policy. for
+// parallel edges, the adjacency list of 'v' (and for undirected
+// graphs, the shorter of those of 'v' and 'w') is scanned. vertices
+// not yet in the graph have no edges, parallel or otherwise.
+//
+func (policy EdgePolicy) check(G GraphOps, v, w int32, directed bool) error {
+	if policy&RejectSelfLoops != 0 && v == w {
+		return ErrSelfLoop
+	}
+
+	if policy&RejectParallelEdges != 0 && v < G.V() && w < G.V() && has_edge(G, v, w, directed) {
+		return ErrParallelEdge
+	}
+
+	return nil
+}
+
+//
+// are vertices 'v' and 'w' in a graph with 'n' vertices
+//
+func in_range(n, v, w int32) bool {
+	return v >= 0 && v < n && w >= 0 && w < n
+}
+
+//
+// vertex 'v' of a graph with 'n' vertices, out of range vertices are
+// rejected with ErrBadVertex
+//
+func check_vertex(n, v int32) error {
+	if v < 0 || v >= n {
+		return fmt.Errorf("%w: %d", ErrBadVertex, v)
+	}
+
+	return nil
+}
+
+//
+// is there at least one edge v-w (or v -> w), there isn't any when
+// either vertex is out of range
+//
+func has_edge(G GraphOps, v, w int32, directed bool) bool {
+	if !in_range(G.V(), v, w) {
+		return false
+	}
+
+	if !directed && len(G.Adj(w)) < len(G.Adj(v)) {
+		v, w = w, v
+	}
+
+	for _, x := range G.Adj(v) {
+		if x == w {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// number of edges v-w (or v -> w). for undirected graphs, each
+// self-loop shows up twice in the adjacency list. out of range
+// vertices have no edges.
+//
+func multiplicity(G GraphOps, v, w int32, directed bool) (n int32) {
+	if !in_range(G.V(), v, w) {
+		return
+	}
+
+	if !directed && len(G.Adj(w)) < len(G.Adj(v)) {
+		v, w = w, v
+	}
+
+	for _, x := range G.Adj(v) {
+		if x == w {
+			n += 1
+		}
+	}
+
+	if !directed && v == w {
+		n /= 2
+	}
+
+	return
+}
+
+//
+// remove all occurences of 'w' from the vertex list, preserving the
+// order of the rest, and return the number removed
+//
+func (vl *vertex_list_t) remove_all(w int32) (n int32) {
+	kept := (*vl)[:0]
+
+	for _, x := range *vl {
+		if x != w {
+			kept = append(kept, x)
+		}
+	}
+
+	n = int32(len(*vl) - len(kept))
+	*vl = kept
+
+	return
+}
+
+//
+// drop the (already isolated) vertex 'v' from the adjacency lists,
+// renumbering vertices after 'v' down by one. returns the mapping of
+// old vertex ids to new ones (-1 for 'v').
+//
+func remove_isolated_vertex(adj []vertex_list_t, v int32) (new_adj []vertex_list_t, old_to_new []int32) {
+	old_to_new = make([]int32, len(adj))
+	for u := range old_to_new {
+		switch {
+		case int32(u) < v:
+			old_to_new[u] = int32(u)
+		case int32(u) == v:
+			old_to_new[u] = -1
+		default:
+			old_to_new[u] = int32(u) - 1
+		}
+	}
+
+	new_adj = append(adj[:v], adj[v+1:]...)
+	for _, vl := range new_adj {
+		for i, x := range vl {
+			vl[i] = old_to_new[x]
+		}
+	}
+
+	return
+}
+
+//
+// remove the first occurence of 'w' from the vertex list, preserving
+// the order of the rest. returns false if 'w' isn't there.
+//
+func (vl *vertex_list_t) remove(w int32) bool {
+	for i, x := range *vl {
+		if x == w {
+			*vl = append((*vl)[:i], (*vl)[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
 func average_degree(G GraphOps) float64 {
 	return float64(2 * G.V() / G.E())
 }
@@ -64,60 +275,112 @@ func maximum_degree(G GraphOps) (max_degree int32) {
 //     <line-004> vertex-3 : adj-list-of(vertex-3)
 //
 func graph_stringifier(G GraphOps) string {
-	str := fmt.Sprintf("%d vertices, %d edges\n", G.V(), G.E())
+	var sb strings.Builder
 
-	for v := int32(0); v < G.V(); v++ {
-		for _, w := range G.Adj(v) {
-			str += fmt.Sprintf("%d ", w)
-		}
-		str += fmt.Sprintf("\n")
-	}
+	write_adjacency(&sb, G)
+	return sb.String()
+}
 
-	return str
+// streaming version of graph_stringifier(...)
+func write_adjacency(dst io.Writer, G GraphOps) (int64, error) {
+	return write_buffered(dst, func(w *bufio.Writer) {
+		scratch := make([]byte, 0, 16)
+
+		fmt.Fprintf(w, "%d vertices, %d edges\n", G.V(), G.E())
+		for v := int32(0); v < G.V(); v++ {
+			for _, x := range G.Adj(v) {
+				scratch = strconv.AppendInt(scratch[:0], int64(x), 10)
+				scratch = append(scratch, ' ')
+				w.Write(scratch)
+			}
+			w.WriteByte('\n')
+		}
+	})
 }
 
 //
-// parse the input data file which is expected to be in the following
-// format:
-//     <line-001> number-of-vertices (V)
-//     <line-002> number-of-edges (E)
-//     <line-003> vertex-i vertex-j (edges[][2])
-//     ....................
+// emit the graph structure in a format suitable for subsequent
+// loading from LoadFromXXX(...) invokation. output format is as
+// following
+//
+//     <line-001> V
+//     <line-002> E
+//     <line-003> vertex-i vertex-j
 //     ....................
-//     <line-N>   vertex-i vertex-j (edges[][2])
 //
-// commented-lines (starting with '#') / non-empty lines are ignored
+// for undirected graphs, each edge is emitted just once
 //
-func parse_graph_datafile(in *bufio.Reader) (V int32, E int32, Edges [][2]int32, err error) {
-	var values []int32
-	var comment_char byte = '#'
+func write_edge_list(dst io.Writer, G GraphOps, directed bool) (int64, error) {
+	return write_buffered(dst, func(w *bufio.Writer) {
+		scratch := make([]byte, 0, 32)
 
-	if in == nil {
-		return
-	}
+		fmt.Fprintf(w, "%d\n%d\n", G.V(), G.E())
+		for_each_edge(G, directed, func(v, x int32) {
+			scratch = strconv.AppendInt(scratch[:0], int64(v), 10)
+			scratch = append(scratch, ' ')
+			scratch = strconv.AppendInt(scratch, int64(x), 10)
+			scratch = append(scratch, '\n')
+			w.Write(scratch)
+		})
+	})
+}
 
-	// parse vertex-count
-	values, err = line_parser.Int32sFromReader(in, comment_char)
-	if err != nil {
-		return
-	}
-	V = values[0]
+//
+// an io.Writer keeping count of the bytes written through it, so that
+// io.WriterTo implementations can report it
+//
+type counting_writer_t struct {
+	w io.Writer
+	n int64
+}
 
-	// parse edge-count
-	values, err = line_parser.Int32sFromReader(in, comment_char)
-	if err != nil {
-		return
-	}
-	E = values[0]
+func (cw *counting_writer_t) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += int64(n)
+
+	return
+}
+
+//
+// run 'fn' with a buffered writer on top of 'dst', and return total
+// number of bytes written to 'dst'. errors on the buffered writer are
+// sticky, so 'fn' needn't bother checking them.
+//
+func write_buffered(dst io.Writer, fn func(w *bufio.Writer)) (n int64, err error) {
+	cw := &counting_writer_t{w: dst}
+	w := bufio.NewWriter(cw)
+
+	fn(w)
+	err = w.Flush()
+	n = cw.n
+
+	return
+}
+
+//
+// invoke 'fn' once for each edge of the graph in adjacency order. for
+// undirected graphs, where an edge v-w shows up in the adjacency
+// list of both v and w, only the v <= w copy is reported. self-loops
+// show up twice in v's adjacency list, so every other one is
+// reported.
+//
+func for_each_edge(G GraphOps, directed bool, fn func(v, w int32)) {
+	for v := int32(0); v < G.V(); v++ {
+		self_loops := 0
+
+		for _, w := range G.Adj(v) {
+			if !directed && v > w {
+				continue
+			}
+
+			if !directed && v == w {
+				if self_loops += 1; self_loops%2 == 0 {
+					continue
+				}
+			}
 
-	// parse edge-list
-	Edges = make([][2]int32, E)
-	for i := int32(0); i < E; i++ {
-		values, err = line_parser.Int32sFromReader(in, comment_char)
-		if err != nil {
-			break
+			fn(v, w)
 		}
-		Edges[i][0], Edges[i][1] = values[0], values[1]
 	}
 
 	return
@@ -38,3 +38,13 @@ type GraphOps interface {
 	E() int32          // number of edges
 	Adj(int32) []int32 // adjacency list
 }
+
+//
+// operations on graphs where each edge carries a weight. weights of
+// the edges out of a vertex are aligned with its adjacency list
+// i.e. Weights(v)[i] is the weight of the edge v -> Adj(v)[i]
+//
+type WeightedGraphOps interface {
+	GraphOps
+	Weights(int32) []float64 // edge weights
+}
@@ -0,0 +1,328 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements the parser for graph data files, with a
+// strict mode which rejects any malformed input, and a lenient mode
+// which skips it while collecting warnings.
+//
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//
+// how the parser deals with malformed input
+//
+type ParseMode int
+
+const (
+	ParseStrict  ParseMode = iota // first problem aborts the parse
+	ParseLenient                  // problems are skipped, and reported as warnings
+)
+
+//
+// a problem encountered while parsing a graph data file
+//
+type ParseError struct {
+	Line   int    // line number (starting at 1), 0 if not tied to a line
+	Text   string // offending text
+	Reason string // what is wrong with it
+}
+
+func (pe *ParseError) Error() string {
+	if pe.Line == 0 {
+		return fmt.Sprintf("parse: %s", pe.Reason)
+	}
+
+	return fmt.Sprintf("parse: line %d: %s: %q", pe.Line, pe.Reason, pe.Text)
+}
+
+//
+// create an undirected graph from its serialized definition (see
+// parse_graph_datafile(...) for the format). in the strict mode, the
+// first problem is returned as a *ParseError. in the lenient mode,
+// malformed or out-of-range edges are skipped and reported as
+// warnings, while a missing or malformed vertex or edge count still
+// is an error.
+//
+func ParseGraph(src *bufio.Reader, mode ParseMode) (G *Graph, warnings []*ParseError, err error) {
+	var V int32
+	var edges [][2]int32
+
+	if V, edges, warnings, err = parse_graph_datafile(src, mode); err != nil {
+		return
+	}
+
+	G = New(V)
+	for _, e := range edges {
+		G.AddEdge(e[1], e[0])
+	}
+
+	ReverseAdjList(G)
+
+	return
+}
+
+//
+// create a digraph from its serialized definition. modes are the same
+// as those of ParseGraph(...)
+//
+func ParseDigraph(src *bufio.Reader, mode ParseMode) (G *Digraph, warnings []*ParseError, err error) {
+	var V int32
+	var edges [][2]int32
+
+	if V, edges, warnings, err = parse_graph_datafile(src, mode); err != nil {
+		return
+	}
+
+	G = CreateDigraph(V)
+	for _, e := range edges {
+		G.AddEdge(e[0], e[1])
+	}
+
+	ReverseAdjList(G)
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+const comment_char = '#'
+
+type graph_parser_t struct {
+	src      *bufio.Reader
+	mode     ParseMode
+	comment  byte
+	line_no  int
+	warnings []*ParseError
+}
+
+//
+// parse the input data file which is expected to be in the following
+// format:
+//     <line-001> number-of-vertices (V)
+//     <line-002> number-of-edges (E)
+//     <line-003> vertex-i vertex-j (edges[][2])
+//     ....................
+//     ....................
+//     <line-N>   vertex-i vertex-j (edges[][2])
+//
+// empty lines, and comments (starting with '#', till the end of the
+// line) are ignored. all returned edges refer to vertices in the
+// range [0, V). no input at all (a nil reader) is an empty graph.
+//
+func parse_graph_datafile(in *bufio.Reader, mode ParseMode) (V int32, Edges [][2]int32, warnings []*ParseError, err error) {
+	var E int32
+	var text string
+	var ok bool
+
+	if in == nil {
+		return
+	}
+
+	p := &graph_parser_t{src: in, mode: mode, comment: comment_char}
+	defer func() { warnings = p.warnings }()
+
+	if V, err = p.parse_count("vertex count"); err != nil {
+		return
+	}
+
+	if E, err = p.parse_count("edge count"); err != nil {
+		return
+	}
+
+	// don't trust E for the up-front allocation
+	Edges = make([][2]int32, 0, min_int32(E, 1<<16))
+
+	for {
+		if text, ok, err = p.next_line(); err != nil || !ok {
+			break
+		}
+
+		if int32(len(Edges)) == E {
+			err = p.complain(text, fmt.Sprintf("more than %d edges", E))
+			break
+		}
+
+		var edge [2]int32
+		if edge, ok, err = p.parse_edge(text, V); err != nil {
+			break
+		}
+
+		if ok {
+			Edges = append(Edges, edge)
+		}
+	}
+
+	if err != nil {
+		Edges = nil
+		return
+	}
+
+	if int32(len(Edges)) < E {
+		err = p.report(&ParseError{Reason: fmt.Sprintf("expected %d edges, found %d", E, len(Edges))})
+		if err != nil {
+			Edges = nil
+		}
+	}
+
+	return
+}
+
+//
+// return the next non-empty line with comments stripped. 'ok' is
+// false once the input is exhausted
+//
+func (p *graph_parser_t) next_line() (text string, ok bool, err error) {
+	for {
+		text, err = p.src.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return
+		}
+
+		last_line := (err == io.EOF)
+		if last_line && len(text) == 0 {
+			return "", false, nil
+		}
+		p.line_no++
+
+		if i := strings.IndexByte(text, p.comment); i >= 0 {
+			text = text[:i]
+		}
+
+		if text = strings.TrimSpace(text); len(text) != 0 {
+			return text, true, nil
+		}
+
+		if last_line {
+			return "", false, nil
+		}
+	}
+}
+
+//
+// report a problem at the current line. in the strict mode, the
+// problem is returned as an error, while it is recorded as a warning
+// otherwise
+//
+func (p *graph_parser_t) complain(text, reason string) error {
+	return p.report(&ParseError{Line: p.line_no, Text: text, Reason: reason})
+}
+
+func (p *graph_parser_t) report(pe *ParseError) error {
+	if p.mode == ParseStrict {
+		return pe
+	}
+
+	p.warnings = append(p.warnings, pe)
+	return nil
+}
+
+// vertex and edge counts are mandatory, even in the lenient mode
+func (p *graph_parser_t) parse_count(what string) (count int32, err error) {
+	var text string
+	var ok bool
+
+	if text, ok, err = p.next_line(); err != nil {
+		return
+	}
+
+	if !ok {
+		return 0, &ParseError{Line: p.line_no, Reason: "missing " + what}
+	}
+
+	tokens := strings.Fields(text)
+	if count, err = parse_int32(tokens[0]); err != nil {
+		return 0, &ParseError{Line: p.line_no, Text: text, Reason: "malformed " + what}
+	}
+
+	if count < 0 {
+		return 0, &ParseError{Line: p.line_no, Text: text, Reason: "negative " + what}
+	}
+
+	if len(tokens) > 1 {
+		err = p.complain(text, "trailing tokens after "+what)
+	}
+
+	return
+}
+
+//
+// parse a 'v w' edge, checking both vertices against 'V'. 'ok' is
+// false for edges which the lenient mode skipped
+//
+func (p *graph_parser_t) parse_edge(text string, V int32) (edge [2]int32, ok bool, err error) {
+	tokens := strings.Fields(text)
+
+	if len(tokens) < 2 {
+		err = p.complain(text, "expected two vertices")
+		return
+	}
+
+	for i := 0; i < 2; i++ {
+		var perr error
+
+		if edge[i], perr = parse_int32(tokens[i]); perr != nil {
+			err = p.complain(text, fmt.Sprintf("non-integer token %q", tokens[i]))
+			return
+		}
+
+		if edge[i] < 0 || edge[i] >= V {
+			err = p.complain(text, fmt.Sprintf("vertex %d out of range [0, %d)", edge[i], V))
+			return
+		}
+	}
+
+	if len(tokens) > 2 {
+		if err = p.complain(text, "trailing tokens after edge"); err != nil {
+			return
+		}
+	}
+
+	return edge, true, nil
+}
+
+func parse_int32(token string) (int32, error) {
+	val, err := strconv.ParseInt(token, 10, 32)
+	return int32(val), err
+}
+
+func min_int32(x, y int32) int32 {
+	if x < y {
+		return x
+	}
+
+	return y
+}
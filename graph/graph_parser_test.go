@@ -0,0 +1,161 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleParseGraph() {
+	data := "3\n4\n0 1\n1 3\n1 two\n2 0\n"
+
+	_, _, err := ParseGraph(bufio.NewReader(strings.NewReader(data)), ParseStrict)
+	fmt.Println(err)
+
+	g, warnings, _ := ParseGraph(bufio.NewReader(strings.NewReader(data)), ParseLenient)
+	for _, w := range warnings {
+		fmt.Println(w)
+	}
+	fmt.Println(g.E(), g.Adj(0))
+
+	// Output:
+	// parse: line 4: vertex 3 out of range [0, 3): "1 3"
+	// parse: line 4: vertex 3 out of range [0, 3): "1 3"
+	// parse: line 5: non-integer token "two": "1 two"
+	// parse: expected 4 edges, found 2
+	// 2 [2 1]
+}
+
+//
+// the strict mode must pin-point the first problem in its input
+//
+func TestParseStrict(t *testing.T) {
+	test_cases := []struct {
+		data   string
+		line   int
+		reason string
+	}{
+		{"", 0, "missing vertex count"},
+		{"# nothing but comments\n\n", 2, "missing vertex count"},
+		{"x\n", 1, "malformed vertex count"},
+		{"-3\n", 1, "negative vertex count"},
+		{"3 4\n", 1, "trailing tokens after vertex count"},
+		{"3\n", 1, "missing edge count"},
+		{"3\n\n# edges\nfoo\n", 4, "malformed edge count"},
+		{"3\n1\n0\n", 3, "expected two vertices"},
+		{"3\n1\n0 x\n", 3, "non-integer token"},
+		{"3\n1\n0 99999999999\n", 3, "non-integer token"},
+		{"3\n1\n0 3\n", 3, "vertex 3 out of range"},
+		{"3\n1\n-1 2\n", 3, "vertex -1 out of range"},
+		{"3\n1\n0 1 2\n", 3, "trailing tokens after edge"},
+		{"3\n1\n0 1\n1 2\n", 4, "more than 1 edges"},
+		{"3\n2\n0 1\n", 0, "expected 2 edges, found 1"},
+	}
+
+	for i, tc := range test_cases {
+		_, _, err := ParseDigraph(bufio.NewReader(strings.NewReader(tc.data)), ParseStrict)
+
+		pe, ok := err.(*ParseError)
+		if !ok || pe.Line != tc.line || !strings.Contains(pe.Reason, tc.reason) {
+			t.Logf("test-case: %d, expected: line %d: %s, got: %v\n", i, tc.line, tc.reason, err)
+			t.Fail()
+		}
+	}
+}
+
+//
+// the lenient mode must skip what it doesn't like, and say so
+//
+func TestParseLenient(t *testing.T) {
+	data := "# comment\n4\n5 # edges\n0 1 # trailing comment\n1 4\n2\n2 3 3\n3 0"
+
+	g, warnings, err := ParseGraph(bufio.NewReader(strings.NewReader(data)), ParseLenient)
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	want_lines := []int{5, 6, 7, 0}
+	if len(warnings) != len(want_lines) {
+		t.Logf("expected %d warnings, got: %v\n", len(want_lines), warnings)
+		t.FailNow()
+	}
+
+	for i, w := range warnings {
+		if w.Line != want_lines[i] {
+			t.Logf("warning: %d, expected line: %d, got: %v\n", i, want_lines[i], w)
+			t.Fail()
+		}
+	}
+
+	// last line has no trailing newline, and must still count
+	want := create_test_graph(&graph_definition{4, 3, [][2]int32{{0, 1}, {2, 3}, {3, 0}}})
+	if !cmp_graph(g, want) {
+		t.Logf("expected:\n%s\ngot:\n%s\n", want, g)
+		t.Fail()
+	}
+}
+
+// no input at all is an empty graph, as it has always been
+func TestParseNilReader(t *testing.T) {
+	g, err := LoadFromReader(nil)
+	if err != nil || g.V() != 0 || g.E() != 0 {
+		t.Logf("unexpected graph: %v, error: %v\n", g, err)
+		t.Fail()
+	}
+
+	d, err := LoadDigraphFromReader(nil)
+	if err != nil || d.V() != 0 || d.E() != 0 {
+		t.Logf("unexpected digraph: %v, error: %v\n", d, err)
+		t.Fail()
+	}
+}
+
+// shipped data files must be clean
+func TestParseFixtures(t *testing.T) {
+	for _, fname := range []string{
+		"data/graph-001.data",
+		"data/graph-002.data",
+		"data/graph-003.data",
+		"data/graph-004.data",
+	} {
+		f, err := OpenFile(fname)
+		if err != nil {
+			t.Logf("file: %s, unexpected error: %s\n", fname, err)
+			t.Fail()
+			continue
+		}
+
+		if _, _, err = ParseGraph(bufio.NewReader(f), ParseStrict); err != nil {
+			t.Logf("file: %s, unexpected error: %s\n", fname, err)
+			t.Fail()
+		}
+		f.Close()
+	}
+}
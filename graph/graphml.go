@@ -0,0 +1,438 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements reading and writing graphs and digraphs in
+// the graphml format (as understood by yed, gephi etc.)
+//
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+const graphml_xmlns = "http://graphml.graphdrawing.org/xmlns"
+
+// name of the node attribute carrying vertex labels
+const graphml_label_attr = "label"
+
+//
+// graphml document structure, only the parts that we care about are
+// described here, everything else is ignored while reading
+//
+type graphml_doc_t struct {
+	XMLName xml.Name        `xml:"graphml"`
+	Xmlns   string          `xml:"xmlns,attr,omitempty"`
+	Keys    []graphml_key_t `xml:"key"`
+	Graph   graphml_graph_t `xml:"graph"`
+}
+
+//
+// graphml has no list types, tags are emitted as a json array of
+// strings, with 'attr.list' marking such keys
+//
+type graphml_key_t struct {
+	Id   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+	List string `xml:"attr.list,attr,omitempty"`
+}
+
+type graphml_graph_t struct {
+	Id          string           `xml:"id,attr,omitempty"`
+	EdgeDefault string           `xml:"edgedefault,attr"`
+	Nodes       []graphml_node_t `xml:"node"`
+	Edges       []graphml_edge_t `xml:"edge"`
+}
+
+type graphml_node_t struct {
+	Id   string           `xml:"id,attr"`
+	Data []graphml_data_t `xml:"data"`
+}
+
+type graphml_edge_t struct {
+	Source string           `xml:"source,attr"`
+	Target string           `xml:"target,attr"`
+	Data   []graphml_data_t `xml:"data"`
+}
+
+type graphml_data_t struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+//
+// this function emits the graph structure in graphml format. when
+// 'labels' is non-nil, labels[v] is emitted as the label of vertex
+// 'v'. vertex and edge attributes (if any) are emitted as data, with
+// the attribute name "label" reserved for vertex labels.
+//
+func (G *Graph) WriteGraphML(w io.Writer, labels []string) error {
+	return write_graphml(w, G, false, labels, G.attrs)
+}
+
+//
+// this function emits the digraph structure in graphml format. when
+// 'labels' is non-nil, labels[v] is emitted as the label of vertex
+// 'v'. vertex and edge attributes (if any) are emitted as data, with
+// the attribute name "label" reserved for vertex labels.
+//
+func (G *Digraph) WriteGraphML(w io.Writer, labels []string) error {
+	return write_graphml(w, G, true, labels, G.attrs)
+}
+
+//
+// this function is called to create a graph from its graphml
+// definition. vertices are numbered in the order in which they are
+// declared. vertex labels are returned if the document has them, nil
+// otherwise. other node and edge data become their attributes.
+//
+func LoadFromGraphML(src io.Reader) (new_graph *Graph, labels []string, err error) {
+	var V int32
+	var edges [][2]int32
+	var attrs *Attributes
+
+	if V, edges, labels, attrs, err = parse_graphml(src, false); err != nil {
+		return
+	}
+
+	new_graph = New(V)
+	new_graph.attrs = attrs
+	for _, e := range edges {
+		new_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// this function is called to create a digraph from its graphml
+// definition. vertices are numbered in the order in which they are
+// declared. vertex labels are returned if the document has them, nil
+// otherwise. other node and edge data become their attributes.
+//
+func LoadDigraphFromGraphML(src io.Reader) (new_graph *Digraph, labels []string, err error) {
+	var V int32
+	var edges [][2]int32
+	var attrs *Attributes
+
+	if V, edges, labels, attrs, err = parse_graphml(src, true); err != nil {
+		return
+	}
+
+	new_graph = CreateDigraph(V)
+	new_graph.attrs = attrs
+	for _, e := range edges {
+		new_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// emit 'G' as a graphml document
+func write_graphml(w io.Writer, G GraphOps, directed bool, labels []string, attrs *Attributes) (err error) {
+	doc := graphml_doc_t{
+		Xmlns: graphml_xmlns,
+		Graph: graphml_graph_t{
+			Id:          "G",
+			EdgeDefault: "undirected",
+			Nodes:       make([]graphml_node_t, G.V()),
+		},
+	}
+
+	if directed {
+		doc.Graph.EdgeDefault = "directed"
+	}
+
+	if labels != nil {
+		doc.Keys = []graphml_key_t{
+			{Id: graphml_label_attr, For: "node", Name: graphml_label_attr, Type: "string"},
+		}
+	}
+
+	// attribute-name -> key-id, for vertices and edges
+	var vertex_keys, edge_keys map[string]string
+	if attrs != nil {
+		if vertex_keys, edge_keys, err = graphml_attr_keys(&doc, attrs); err != nil {
+			return fmt.Errorf("graphml: %s", err)
+		}
+	}
+
+	for v := int32(0); v < G.V(); v++ {
+		node := &doc.Graph.Nodes[v]
+		node.Id = graphml_node_id(v)
+
+		if labels != nil {
+			node.Data = []graphml_data_t{{Key: graphml_label_attr, Value: labels[v]}}
+		}
+		if attrs != nil {
+			node.Data = append(node.Data, graphml_attr_data(attrs.VertexAttrs(v), vertex_keys)...)
+		}
+	}
+
+	for_each_edge(G, directed, func(v, w int32) {
+		edge := graphml_edge_t{
+			Source: graphml_node_id(v),
+			Target: graphml_node_id(w),
+		}
+		if attrs != nil {
+			edge.Data = graphml_attr_data(attrs.EdgeAttrs(v, w), edge_keys)
+		}
+
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	})
+
+	return write_xml_doc(w, &doc)
+}
+
+//
+// declare keys for all the vertex, and edge attributes in use, and
+// return the key ids for each attribute name
+//
+func graphml_attr_keys(doc *graphml_doc_t, attrs *Attributes) (vertex_keys, edge_keys map[string]string, err error) {
+	var vertex, edge []AttrSchema
+
+	if vertex, edge, err = attrs.Schema(); err != nil {
+		return
+	}
+
+	declare := func(schema []AttrSchema, what, prefix string) map[string]string {
+		keys := make(map[string]string, len(schema))
+
+		for i, s := range schema {
+			key := graphml_key_t{Id: prefix + strconv.Itoa(i), For: what, Name: s.Name, Type: "string"}
+
+			switch s.Kind {
+			case AttrNumber:
+				key.Type = "double"
+			case AttrTags:
+				key.List = "string"
+			}
+
+			doc.Keys = append(doc.Keys, key)
+			keys[s.Name] = key.Id
+		}
+
+		return keys
+	}
+
+	for _, s := range vertex {
+		if s.Name == graphml_label_attr {
+			err = fmt.Errorf("vertex attribute '%s' is reserved for vertex labels", s.Name)
+			return
+		}
+	}
+
+	vertex_keys, edge_keys = declare(vertex, "node", "v"), declare(edge, "edge", "e")
+	return
+}
+
+// data elements for attributes of a vertex or an edge, sorted by key
+func graphml_attr_data(m AttrMap, keys map[string]string) (data []graphml_data_t) {
+	for name, val := range m {
+		d := graphml_data_t{Key: keys[name], Value: val.String()}
+
+		if val.Kind == AttrTags {
+			doc, _ := json.Marshal(append(make([]string, 0, len(val.Tags)), val.Tags...))
+			d.Value = string(doc)
+		}
+
+		data = append(data, d)
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i].Key < data[j].Key })
+	return
+}
+
+//
+// attribute value for graphml data of a given key. numeric types
+// become numbers, lists become tags, and everything else is taken as
+// a string.
+//
+func graphml_attr_value(key *graphml_key_t, value string) (val Attr, err error) {
+	switch {
+	case len(key.List) > 0:
+		var tags []string
+		if err = json.Unmarshal([]byte(value), &tags); err != nil {
+			err = fmt.Errorf("bad list value for key '%s': %q", key.Id, value)
+		}
+		val = TagsAttr(tags...)
+
+	case key.Type == "int" || key.Type == "long" || key.Type == "float" || key.Type == "double":
+		var x float64
+		if x, err = strconv.ParseFloat(value, 64); err != nil {
+			err = fmt.Errorf("bad %s value for key '%s': %q", key.Type, key.Id, value)
+		}
+		val = NumberAttr(x)
+
+	default:
+		val = StringAttr(value)
+	}
+
+	return
+}
+
+// graphml node ids are of the form n0, n1, ...
+func graphml_node_id(v int32) string { return "n" + strconv.Itoa(int(v)) }
+
+//
+// parse a graphml document, ensuring that it describes the expected
+// kind of graph
+//
+func parse_graphml(src io.Reader, directed bool) (V int32, edges [][2]int32, labels []string, attrs *Attributes, err error) {
+	var doc graphml_doc_t
+
+	if err = xml.NewDecoder(src).Decode(&doc); err != nil {
+		err = fmt.Errorf("graphml: %s", err)
+		return
+	}
+
+	if is_directed := doc.Graph.EdgeDefault == "directed"; is_directed != directed {
+		err = fmt.Errorf("graphml: unexpected edgedefault: '%s'", doc.Graph.EdgeDefault)
+		return
+	}
+
+	// key used for vertex labels (if any), and the attribute keys
+	label_key := ""
+	vertex_keys := make(map[string]*graphml_key_t)
+	edge_keys := make(map[string]*graphml_key_t)
+
+	for i := range doc.Keys {
+		k := &doc.Keys[i]
+
+		if (k.For == "node" || k.For == "all") && k.Name == graphml_label_attr {
+			label_key = k.Id
+			continue
+		}
+		if k.For == "node" || k.For == "all" {
+			vertex_keys[k.Id] = k
+		}
+		if k.For == "edge" || k.For == "all" {
+			edge_keys[k.Id] = k
+		}
+	}
+
+	// attribute of a vertex or an edge, from its data
+	attrs = new_attributes(directed)
+	add_attr := func(keys map[string]*graphml_key_t, d *graphml_data_t, set func(string, Attr)) error {
+		key, ok := keys[d.Key]
+		if !ok {
+			return nil
+		}
+
+		val, err := graphml_attr_value(key, d.Value)
+		if err == nil {
+			set(key.Name, val)
+		}
+		return err
+	}
+
+	// node-id -> vertex
+	vertex_of := make(map[string]int32, len(doc.Graph.Nodes))
+	if len(label_key) > 0 {
+		labels = make([]string, len(doc.Graph.Nodes))
+	}
+
+	for i, node := range doc.Graph.Nodes {
+		if _, dup := vertex_of[node.Id]; dup {
+			err = fmt.Errorf("graphml: duplicate node: '%s'", node.Id)
+			return
+		}
+		vertex_of[node.Id] = int32(i)
+
+		v := int32(i)
+		for j := range node.Data {
+			d := &node.Data[j]
+
+			if len(label_key) > 0 && d.Key == label_key {
+				labels[i] = d.Value
+				continue
+			}
+
+			err = add_attr(vertex_keys, d, func(name string, val Attr) { attrs.SetVertex(v, name, val) })
+			if err != nil {
+				err = fmt.Errorf("graphml: node '%s': %s", node.Id, err)
+				return
+			}
+		}
+	}
+
+	V = int32(len(doc.Graph.Nodes))
+	edges = make([][2]int32, len(doc.Graph.Edges))
+
+	for i, e := range doc.Graph.Edges {
+		v, v_ok := vertex_of[e.Source]
+		w, w_ok := vertex_of[e.Target]
+
+		if !v_ok || !w_ok {
+			err = fmt.Errorf("graphml: edge %s -> %s refers to an undeclared node", e.Source, e.Target)
+			return
+		}
+		edges[i] = [2]int32{v, w}
+
+		for j := range e.Data {
+			err = add_attr(edge_keys, &e.Data[j], func(name string, val Attr) { attrs.SetEdge(v, w, name, val) })
+			if err != nil {
+				err = fmt.Errorf("graphml: edge %s -> %s: %s", e.Source, e.Target, err)
+				return
+			}
+		}
+	}
+
+	if len(attrs.vertex) == 0 && len(attrs.edge) == 0 {
+		attrs = nil
+	}
+
+	return
+}
+
+// emit an xml document, with the xml header and indentation
+func write_xml_doc(dst io.Writer, doc interface{}) (err error) {
+	if _, err = io.WriteString(dst, xml.Header); err != nil {
+		return
+	}
+
+	enc := xml.NewEncoder(dst)
+	enc.Indent("", "  ")
+
+	if err = enc.Encode(doc); err != nil {
+		return
+	}
+
+	_, err = io.WriteString(dst, "\n")
+	return
+}
@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// a digraph with a self-loop and parallel edges
+func create_xml_test_digraph() *Digraph {
+	g := CreateDigraph(5)
+	for _, e := range [][2]int32{{0, 1}, {1, 2}, {2, 0}, {2, 2}, {3, 4}, {3, 4}} {
+		g.AddEdge(e[0], e[1])
+	}
+
+	return g
+}
+
+//
+// write graphs in graphml, read them back and compare the two for
+// equality.
+//
+func TestGraphMLRoundTrip(t *testing.T) {
+	for i, graph := range graphs {
+		g1 := create_test_graph(&graph.graph_defn)
+		g1.AddEdge(1, 1)
+
+		var buf bytes.Buffer
+		if err := g1.WriteGraphML(&buf, nil); err != nil {
+			t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+			t.FailNow()
+		}
+
+		g2, labels, err := LoadFromGraphML(&buf)
+		if err != nil || labels != nil || !cmp_graph(g1, g2) {
+			t.Logf("test-graph: %d, error: %v, labels: %v\n", i, err, labels)
+			t.Logf("original-graph:\n%s", g1)
+			t.Logf("new-graph:\n%s", g2)
+			t.Fail()
+		}
+	}
+
+	dg1 := create_xml_test_digraph()
+	names := []string{"a", "b", "<c>", "d & e", "f"}
+
+	var buf bytes.Buffer
+	dg1.WriteGraphML(&buf, names)
+
+	dg2, labels, err := LoadDigraphFromGraphML(&buf)
+	if err != nil || !cmp_graph(dg1, dg2) || strings.Join(labels, ",") != strings.Join(names, ",") {
+		t.Logf("digraph round-trip failed, error: %v, labels: %v\n", err, labels)
+		t.Logf("original-graph:\n%s", dg1)
+		t.Logf("new-graph:\n%s", dg2)
+		t.Fail()
+	}
+}
+
+//
+// documents written elsewhere, use their own key and node ids
+//
+func TestGraphMLForeign(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="edge" attr.name="weight" attr.type="double"/>
+  <graph id="G" edgedefault="directed">
+    <node id="alpha"><data key="d0">Alpha</data></node>
+    <node id="beta"><data key="d0">Beta</data></node>
+    <edge source="beta" target="alpha"><data key="d1">1.5</data></edge>
+  </graph>
+</graphml>`
+
+	g, labels, err := LoadDigraphFromGraphML(strings.NewReader(doc))
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if g.V() != 2 || g.E() != 1 || g.Adj(1)[0] != 0 || labels[0] != "Alpha" || labels[1] != "Beta" {
+		t.Logf("bad graph: %s, labels: %v\n", g, labels)
+		t.Fail()
+	}
+
+	// wrong kind of graph
+	if _, _, err := LoadFromGraphML(strings.NewReader(doc)); err == nil {
+		t.Logf("expected error loading a digraph as graph\n")
+		t.Fail()
+	}
+
+	// dangling edge
+	bad := strings.Replace(doc, `target="alpha"`, `target="gamma"`, 1)
+	if _, _, err := LoadDigraphFromGraphML(strings.NewReader(bad)); err == nil {
+		t.Logf("expected error for an undeclared node\n")
+		t.Fail()
+	}
+}
@@ -0,0 +1,553 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements reading and writing graphs and digraphs in
+// the json graph format (jgf). both, reading and writing are
+// streaming i.e. the document is never held in memory as a whole.
+//
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+//
+// this function emits the graph structure as a jgf document. when
+// 'labels' is non-nil, labels[v] is emitted as the label of vertex
+// 'v'. vertex and edge attributes (if any) are emitted as their
+// metadata.
+//
+func (G *Graph) WriteJSON(w io.Writer, labels []string) error {
+	return write_jgf(w, G, false, labels, G.attrs)
+}
+
+//
+// this function emits the digraph structure as a jgf document. when
+// 'labels' is non-nil, labels[v] is emitted as the label of vertex
+// 'v'. vertex and edge attributes (if any) are emitted as their
+// metadata.
+//
+func (G *Digraph) WriteJSON(w io.Writer, labels []string) error {
+	return write_jgf(w, G, true, labels, G.attrs)
+}
+
+//
+// this function is called to create a graph from its jgf
+// definition. vertices are numbered in the order in which they are
+// declared. vertex labels are returned if the document has them, nil
+// otherwise. string, number, and list-of-string valued metadata of
+// nodes and edges become their attributes, other metadata is ignored.
+//
+func LoadFromJSON(src io.Reader) (new_graph *Graph, labels []string, err error) {
+	var V int32
+	var edges [][2]int32
+	var attrs *Attributes
+
+	if V, edges, labels, attrs, err = parse_jgf(src, false); err != nil {
+		return
+	}
+
+	new_graph = New(V)
+	new_graph.attrs = attrs
+	for _, e := range edges {
+		new_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// this function is called to create a digraph from its jgf
+// definition. vertices are numbered in the order in which they are
+// declared. vertex labels are returned if the document has them, nil
+// otherwise. string, number, and list-of-string valued metadata of
+// nodes and edges become their attributes, other metadata is ignored.
+//
+func LoadDigraphFromJSON(src io.Reader) (new_graph *Digraph, labels []string, err error) {
+	var V int32
+	var edges [][2]int32
+	var attrs *Attributes
+
+	if V, edges, labels, attrs, err = parse_jgf(src, true); err != nil {
+		return
+	}
+
+	new_graph = CreateDigraph(V)
+	new_graph.attrs = attrs
+	for _, e := range edges {
+		new_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// json.Marshaler and json.Unmarshaler implementations, these are thin
+// wrappers over WriteJSON(...) and LoadXXXFromJSON(...)
+//
+func (G *Graph) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := G.WriteJSON(&buf, nil)
+	return buf.Bytes(), err
+}
+
+func (G *Graph) UnmarshalJSON(data []byte) error {
+	g, _, err := LoadFromJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	*G = *g
+	return nil
+}
+
+func (G *Digraph) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := G.WriteJSON(&buf, nil)
+	return buf.Bytes(), err
+}
+
+func (G *Digraph) UnmarshalJSON(data []byte) error {
+	g, _, err := LoadDigraphFromJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	*G = *g
+	return nil
+}
+
+//
+// private unexported stuff
+//
+
+//
+// emit 'G' as a jgf document, which looks like the following:
+//
+//     {"graph":{"directed":false,
+//               "metadata":{"vertices":V,"edges":E},
+//               "nodes":{"0":{"label":"...","metadata":{...}}, ...},
+//               "edges":[{"source":"0","target":"1","metadata":{...}}, ...]}}
+//
+func write_jgf(dst io.Writer, G GraphOps, directed bool, labels []string, attrs *Attributes) (err error) {
+	w := bufio.NewWriter(dst)
+	scratch := make([]byte, 0, 64)
+
+	// metadata of a vertex or an edge, if it has any attributes
+	write_metadata := func(m AttrMap, sep string) {
+		if len(m) == 0 || err != nil {
+			return
+		}
+
+		var doc []byte
+		if doc, err = jgf_metadata(m); err == nil {
+			w.WriteString(sep + "\"metadata\":")
+			w.Write(doc)
+		}
+	}
+
+	fmt.Fprintf(w, "{\"graph\":{\"directed\":%t,", directed)
+	fmt.Fprintf(w, "\"metadata\":{\"vertices\":%d,\"edges\":%d},", G.V(), G.E())
+
+	// vertices
+	w.WriteString("\"nodes\":{")
+	for v := int32(0); v < G.V(); v++ {
+		if v > 0 {
+			w.WriteByte(',')
+		}
+
+		scratch = append(scratch[:0], '"')
+		scratch = strconv.AppendInt(scratch, int64(v), 10)
+		scratch = append(scratch, "\":{"...)
+		w.Write(scratch)
+
+		sep := ""
+		if labels != nil {
+			label, _ := json.Marshal(labels[v])
+			w.WriteString("\"label\":")
+			w.Write(label)
+			sep = ","
+		}
+		if attrs != nil {
+			write_metadata(attrs.VertexAttrs(v), sep)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteString("},")
+
+	// edges
+	first := true
+	w.WriteString("\"edges\":[")
+	for_each_edge(G, directed, func(v, x int32) {
+		if !first {
+			w.WriteByte(',')
+		}
+		first = false
+
+		scratch = append(scratch[:0], "{\"source\":\""...)
+		scratch = strconv.AppendInt(scratch, int64(v), 10)
+		scratch = append(scratch, "\",\"target\":\""...)
+		scratch = strconv.AppendInt(scratch, int64(x), 10)
+		scratch = append(scratch, '"')
+		w.Write(scratch)
+
+		if attrs != nil {
+			write_metadata(attrs.EdgeAttrs(v, x), ",")
+		}
+		w.WriteByte('}')
+	})
+	w.WriteString("]}}\n")
+
+	if err != nil {
+		return fmt.Errorf("jgf: %s", err)
+	}
+	return w.Flush()
+}
+
+// jgf metadata object for a set of attributes
+func jgf_metadata(m AttrMap) ([]byte, error) {
+	doc := make(map[string]interface{}, len(m))
+
+	for name, val := range m {
+		switch val.Kind {
+		case AttrString:
+			doc[name] = val.Str
+		case AttrNumber:
+			doc[name] = val.Num
+		case AttrTags:
+			doc[name] = append(make([]string, 0, len(val.Tags)), val.Tags...)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+//
+// attributes from jgf metadata. strings, numbers, and arrays of
+// strings are understood, everything else is skipped.
+//
+func jgf_attrs(meta map[string]json.RawMessage) (m AttrMap) {
+	m = make(AttrMap)
+
+	for name, raw := range meta {
+		var val interface{}
+		if json.Unmarshal(raw, &val) != nil {
+			continue
+		}
+
+		switch x := val.(type) {
+		case string:
+			m[name] = StringAttr(x)
+
+		case float64:
+			m[name] = NumberAttr(x)
+
+		case []interface{}:
+			tags := make([]string, 0, len(x))
+			for _, t := range x {
+				if tag, ok := t.(string); ok {
+					tags = append(tags, tag)
+				}
+			}
+			if len(tags) == len(x) {
+				m[name] = TagsAttr(tags...)
+			}
+		}
+	}
+
+	return
+}
+
+// state of a streaming jgf parse
+type jgf_parser_t struct {
+	dec        *json.Decoder
+	directed   *bool // nil, unless the document says so
+	vertex_of  map[string]int32
+	labels     []string
+	has_labels bool
+	edges      [][2]int32
+	pending    []jgf_edge_t // edges seen before the nodes
+	attrs      *Attributes  // from node and edge metadata
+}
+
+// jgf edge
+type jgf_edge_t struct {
+	Source   *string                    `json:"source"`
+	Target   *string                    `json:"target"`
+	Metadata map[string]json.RawMessage `json:"metadata"`
+}
+
+//
+// parse a jgf document, ensuring that it describes the expected kind
+// of graph. nodes may be given as an object keyed by node id (jgf v2)
+// or as an array of objects with an 'id' (jgf v1).
+//
+func parse_jgf(src io.Reader, directed bool) (V int32, edges [][2]int32, labels []string, attrs *Attributes, err error) {
+	p := &jgf_parser_t{
+		dec:       json.NewDecoder(bufio.NewReader(src)),
+		vertex_of: make(map[string]int32),
+		attrs:     new_attributes(directed),
+	}
+
+	if err = p.parse_document(); err != nil {
+		err = fmt.Errorf("jgf: %s", err)
+		return
+	}
+
+	// jgf graphs are directed unless stated otherwise
+	if is_directed := p.directed == nil || *p.directed; is_directed != directed {
+		err = fmt.Errorf("jgf: unexpected directed flag: %t", is_directed)
+		return
+	}
+
+	// resolve edges that were seen before the nodes
+	for i := range p.pending {
+		if err = p.add_edge(&p.pending[i]); err != nil {
+			err = fmt.Errorf("jgf: %s", err)
+			return
+		}
+	}
+
+	V, edges = int32(len(p.vertex_of)), p.edges
+	if len(p.attrs.vertex) > 0 || len(p.attrs.edge) > 0 {
+		attrs = p.attrs
+	}
+	if p.has_labels {
+		labels = p.labels
+	}
+
+	return
+}
+
+// {"graph": {...}}, anything else at the top level is skipped
+func (p *jgf_parser_t) parse_document() error {
+	seen_graph := false
+
+	err := p.parse_object(func(key string) error {
+		if key != "graph" {
+			return p.skip_value()
+		}
+
+		seen_graph = true
+		return p.parse_object(p.parse_graph_member)
+	})
+
+	if err == nil && !seen_graph {
+		err = fmt.Errorf("no 'graph' found")
+	}
+
+	return err
+}
+
+// members of the graph object that we care about
+func (p *jgf_parser_t) parse_graph_member(key string) error {
+	switch key {
+	case "directed":
+		p.directed = new(bool)
+		return p.dec.Decode(p.directed)
+
+	case "nodes":
+		return p.parse_nodes()
+
+	case "edges":
+		return p.parse_array(func() error {
+			var e jgf_edge_t
+
+			if err := p.dec.Decode(&e); err != nil {
+				return err
+			}
+			if e.Source == nil || e.Target == nil {
+				return fmt.Errorf("edge without a source or target")
+			}
+
+			if len(p.vertex_of) == 0 {
+				p.pending = append(p.pending, e)
+				return nil
+			}
+			return p.add_edge(&e)
+		})
+	}
+
+	return p.skip_value()
+}
+
+// jgf node
+type jgf_node_t struct {
+	Id       *string                    `json:"id"`
+	Label    *string                    `json:"label"`
+	Metadata map[string]json.RawMessage `json:"metadata"`
+}
+
+// nodes, either as an object keyed by id, or an array
+func (p *jgf_parser_t) parse_nodes() (err error) {
+	var tok json.Token
+
+	if tok, err = p.dec.Token(); err != nil {
+		return
+	}
+
+	add_node := func(id string, node *jgf_node_t) error {
+		if _, dup := p.vertex_of[id]; dup {
+			return fmt.Errorf("duplicate node: '%s'", id)
+		}
+		v := int32(len(p.labels))
+		p.vertex_of[id] = v
+
+		for name, val := range jgf_attrs(node.Metadata) {
+			p.attrs.SetVertex(v, name, val)
+		}
+
+		label := ""
+		if node.Label != nil {
+			label, p.has_labels = *node.Label, true
+		}
+		p.labels = append(p.labels, label)
+
+		return nil
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		return p.parse_members(func(id string) error {
+			var node jgf_node_t
+			if err := p.dec.Decode(&node); err != nil {
+				return err
+			}
+			return add_node(id, &node)
+		})
+
+	case json.Delim('['):
+		return p.parse_elements(func() error {
+			var node jgf_node_t
+			if err := p.dec.Decode(&node); err != nil {
+				return err
+			}
+			if node.Id == nil {
+				return fmt.Errorf("node without an id")
+			}
+			return add_node(*node.Id, &node)
+		})
+	}
+
+	return fmt.Errorf("unexpected nodes: %v", tok)
+}
+
+// record an edge between two known nodes
+func (p *jgf_parser_t) add_edge(e *jgf_edge_t) error {
+	v, v_ok := p.vertex_of[*e.Source]
+	w, w_ok := p.vertex_of[*e.Target]
+
+	if !v_ok || !w_ok {
+		return fmt.Errorf("edge %s -> %s refers to an undeclared node", *e.Source, *e.Target)
+	}
+
+	for name, val := range jgf_attrs(e.Metadata) {
+		p.attrs.SetEdge(v, w, name, val)
+	}
+
+	p.edges = append(p.edges, [2]int32{v, w})
+	return nil
+}
+
+//
+// an object, invoking 'fn' for each member with the decoder
+// positioned at its value
+//
+func (p *jgf_parser_t) parse_object(fn func(key string) error) error {
+	if err := p.expect_delim('{'); err != nil {
+		return err
+	}
+
+	return p.parse_members(fn)
+}
+
+// members of an object, whose opening '{' has been consumed
+func (p *jgf_parser_t) parse_members(fn func(key string) error) error {
+	for p.dec.More() {
+		tok, err := p.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if err = fn(tok.(string)); err != nil {
+			return err
+		}
+	}
+
+	// closing '}'
+	_, err := p.dec.Token()
+	return err
+}
+
+// an array, invoking 'fn' with the decoder positioned at each element
+func (p *jgf_parser_t) parse_array(fn func() error) error {
+	if err := p.expect_delim('['); err != nil {
+		return err
+	}
+
+	return p.parse_elements(fn)
+}
+
+// elements of an array, whose opening '[' has been consumed
+func (p *jgf_parser_t) parse_elements(fn func() error) error {
+	for p.dec.More() {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	// closing ']'
+	_, err := p.dec.Token()
+	return err
+}
+
+func (p *jgf_parser_t) expect_delim(want json.Delim) error {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != want {
+		return fmt.Errorf("expected '%s', found: %v", want, tok)
+	}
+
+	return nil
+}
+
+// skip over a value we don't care about
+func (p *jgf_parser_t) skip_value() error {
+	var ignored json.RawMessage
+	return p.dec.Decode(&ignored)
+}
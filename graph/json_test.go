@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleGraph_WriteJSON() {
+	g := New(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+
+	g.WriteJSON(os.Stdout, []string{"a", "b", "c"})
+
+	// Output:
+	// {"graph":{"directed":false,"metadata":{"vertices":3,"edges":2},"nodes":{"0":{"label":"a"},"1":{"label":"b"},"2":{"label":"c"}},"edges":[{"source":"0","target":"1"},{"source":"1","target":"2"}]}}
+}
+
+//
+// graphs embedded in other values must go through encoding/json
+// and come back equal
+//
+func TestJSONMarshaler(t *testing.T) {
+	type payload struct {
+		Name    string   `json:"name"`
+		Graph   *Graph   `json:"graph"`
+		Digraph *Digraph `json:"digraph"`
+	}
+
+	for i, graph := range graphs {
+		p1 := payload{
+			Name:    fmt.Sprintf("test-graph-%d", i),
+			Graph:   create_test_graph(&graph.graph_defn),
+			Digraph: create_xml_test_digraph(),
+		}
+
+		data, err := json.Marshal(&p1)
+		if err != nil {
+			t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+			t.FailNow()
+		}
+
+		var p2 payload
+		if err = json.Unmarshal(data, &p2); err != nil {
+			t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+			t.FailNow()
+		}
+
+		if p1.Name != p2.Name || !cmp_graph(p1.Graph, p2.Graph) || !cmp_graph(p1.Digraph, p2.Digraph) {
+			t.Logf("test-graph: %d, round-trip failed\n%s\n", i, data)
+			t.Fail()
+		}
+	}
+}
+
+//
+// jgf v1 documents (nodes as an array), edges ahead of nodes, and
+// unknown members must all be fine
+//
+func TestJSONVariants(t *testing.T) {
+	doc := `{"graph": {
+	  "label": "v1 style",
+	  "edges": [{"source": "x", "target": "y", "metadata": {"weight": 2}}],
+	  "type": "test",
+	  "directed": true,
+	  "nodes": [{"id": "x", "label": "X"}, {"id": "y", "label": "Y"}, {"id": "z"}]
+	}}`
+
+	g, labels, err := LoadDigraphFromJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if g.V() != 3 || g.E() != 1 || g.Adj(0)[0] != 1 || strings.Join(labels, ",") != "X,Y," {
+		t.Logf("bad graph: %s, labels: %q\n", g, labels)
+		t.Fail()
+	}
+
+	bad_docs := []string{
+		strings.Replace(doc, `"target": "y"`, `"target": "w"`, 1),
+		strings.Replace(doc, `"id": "z"`, `"id": "x"`, 1),
+		`{"graphs": []}`,
+		`{"graph": {"nodes": 42}}`,
+		`{"graph": {"nodes": {"0": {}}, "edges": [{"source": "0"}]}}`,
+		`{"graph": {"directed": true`,
+	}
+
+	for _, bad := range bad_docs {
+		if _, _, err := LoadDigraphFromJSON(strings.NewReader(bad)); err == nil {
+			t.Logf("expected error for: %s\n", bad)
+			t.Fail()
+		}
+	}
+
+	// right syntax, wrong kind of graph
+	if _, _, err := LoadFromJSON(strings.NewReader(doc)); err == nil {
+		t.Logf("expected error loading a digraph as graph\n")
+		t.Fail()
+	}
+	// without a 'directed' member, jgf graphs are directed
+	implicit := strings.Replace(doc, `"directed": true,`, "", 1)
+	if g, _, err = LoadDigraphFromJSON(strings.NewReader(implicit)); err != nil || g.E() != 1 {
+		t.Logf("implicitly directed: unexpected error: %v\n", err)
+		t.Fail()
+	}
+
+	if _, _, err = LoadFromJSON(strings.NewReader(implicit)); err == nil {
+		t.Logf("expected error loading an implicitly directed graph as graph\n")
+		t.Fail()
+	}
+}
+
+func BenchmarkWriteJSON(bench *testing.B) {
+	g := New(1 << 12)
+	for v := int32(1); v < g.V(); v++ {
+		g.AddEdge(v, v/2)
+		g.AddEdge(v, (v*7)%g.V())
+	}
+	bench.ResetTimer()
+
+	for i := 0; i < bench.N; i++ {
+		var buf bytes.Buffer
+		g.WriteJSON(&buf, nil)
+	}
+}
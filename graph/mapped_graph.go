@@ -0,0 +1,212 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements a read-only graph backed by a memory-mapped
+// file in the binary csr format (see binary_format.go). adjacency
+// lists point straight into the mapping, so opening a graph costs
+// next to nothing irrespective of its size, and pages are brought in
+// by the kernel as and when they are traversed.
+//
+package graph
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"unsafe"
+)
+
+//
+// a read-only graph (or digraph) backed by a memory-mapped binary
+// file. it implements GraphOps, and thus works with all the
+// traversal and algorithms packages.
+//
+// slices returned by Adj(...) refer to the read-only mapping, and
+// must not be written to. they are capped at their own length, so
+// appending to them is safe. none of them may be used after Close().
+//
+// the attribute section, if any, is covered by the checksum but
+// otherwise skipped, mapped graphs don't carry attributes.
+//
+type MappedGraph struct {
+	v        int32
+	e        int32
+	directed bool
+	offsets  []uint32
+	targets  []int32
+	data     []byte // the mapping itself
+}
+
+//
+// map the binary graph file 'fname' into memory. the header, and the
+// file size implied by it are always checked. when 'verify' is true,
+// offsets, adjacency entries and the checksum (if present) are
+// verified as well, which touches the entire file.
+//
+// without verification, a corrupt file can result in a panic (but
+// never a stray memory access) when its adjacency lists are accessed.
+//
+func OpenMapped(fname string, verify bool) (G *MappedGraph, err error) {
+	var f *os.File
+	var fi os.FileInfo
+	var hdr binary_header_t
+
+	if f, err = os.Open(fname); err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if fi, err = f.Stat(); err != nil {
+		return nil, err
+	}
+
+	if fi.Size() < binary_header_size {
+		return nil, fmt.Errorf("%s: binary: truncated input while reading header", fname)
+	}
+
+	G = &MappedGraph{}
+	if G.data, err = map_file(f, fi.Size()); err != nil {
+		return nil, fmt.Errorf("%s: %s", fname, err)
+	}
+
+	if hdr, err = decode_binary_header(G.data); err == nil {
+		err = G.setup(&hdr, verify)
+	}
+
+	if err != nil {
+		G.Close()
+		return nil, fmt.Errorf("%s: %s", fname, err)
+	}
+
+	return
+}
+
+func (G *MappedGraph) V() int32       { return G.v }
+func (G *MappedGraph) E() int32       { return G.e }
+func (G *MappedGraph) Directed() bool { return G.directed }
+
+func (G *MappedGraph) Adj(v int32) []int32 {
+	lo, hi := G.offsets[v], G.offsets[v+1]
+	return G.targets[lo:hi:hi]
+}
+
+func (G *MappedGraph) String() string { return graph_stringifier(G) }
+
+//
+// release the mapping. the graph, and any adjacency lists obtained
+// from it, must not be used afterwards.
+//
+func (G *MappedGraph) Close() (err error) {
+	if G.data != nil {
+		err = unmap_file(G.data)
+	}
+
+	G.data, G.offsets, G.targets = nil, nil, nil
+	G.v, G.e = 0, 0
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// the binary format is little-endian, which is what the host needs
+// for adjacency lists to point straight into the mapping
+var host_little_endian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+//
+// locate the offsets and targets within the mapping as described by
+// 'hdr', and optionally verify them
+//
+func (G *MappedGraph) setup(hdr *binary_header_t, verify bool) (err error) {
+	if size := int64(len(G.data)); size != hdr.size() {
+		if size < hdr.size() {
+			return fmt.Errorf("binary: truncated input, file size %d, expected %d", size, hdr.size())
+		}
+		return fmt.Errorf("binary: unexpected trailing data, file size %d, expected %d", size, hdr.size())
+	}
+
+	nr_offsets := int(hdr.v) + 1
+	offsets_at := binary_header_size
+	targets_at := offsets_at + 4*nr_offsets
+
+	if host_little_endian {
+		G.offsets = unsafe.Slice((*uint32)(unsafe.Pointer(&G.data[offsets_at])), nr_offsets)
+		if hdr.n > 0 {
+			G.targets = unsafe.Slice((*int32)(unsafe.Pointer(&G.data[targets_at])), hdr.n)
+		}
+	} else {
+		// no zero-copy for you
+		G.offsets = make([]uint32, nr_offsets)
+		for i := range G.offsets {
+			G.offsets[i] = le_uint32(G.data[offsets_at+4*i:])
+		}
+
+		G.targets = make([]int32, hdr.n)
+		for i := range G.targets {
+			G.targets[i] = int32(le_uint32(G.data[targets_at+4*i:]))
+		}
+	}
+
+	// cheap checks making Adj(...) well defined for the first, and
+	// last vertex
+	if G.offsets[0] != 0 || G.offsets[hdr.v] != hdr.n {
+		return hdr.check_offsets(G.offsets)
+	}
+
+	if verify {
+		if err = hdr.check_offsets(G.offsets); err != nil {
+			return
+		}
+
+		if err = hdr.check_targets(G.targets); err != nil {
+			return
+		}
+
+		if hdr.checksum() {
+			body := G.data[:len(G.data)-4]
+			want := crc32.Checksum(body, binary_crc_table)
+
+			if got := le_uint32(G.data[len(body):]); got != want {
+				return fmt.Errorf("binary: checksum mismatch, stored: %#08x, computed: %#08x", got, want)
+			}
+		}
+	}
+
+	G.v, G.e, G.directed = int32(hdr.v), int32(hdr.e), hdr.directed()
+
+	return
+}
+
+func le_uint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
@@ -0,0 +1,127 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// write 'G' in the binary format to a file in 'dir'
+func write_binary_file(t *testing.T, dir string, G interface {
+	WriteBinary(w io.Writer, checksum bool) error
+}, checksum bool) string {
+	fname := filepath.Join(dir, "graph.bin")
+
+	f, err := os.Create(fname)
+	if err == nil {
+		err = G.WriteBinary(f, checksum)
+		f.Close()
+	}
+
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	return fname
+}
+
+//
+// a mapped graph must be indistinguishable from the one it was
+// written from
+//
+func TestMappedGraph(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "mapped-graph")
+	defer os.RemoveAll(dir)
+
+	g := create_bench_graph(1024)
+	d := create_xml_test_digraph()
+
+	for _, verify := range []bool{false, true} {
+		m, err := OpenMapped(write_binary_file(t, dir, g, true), verify)
+		if err != nil {
+			t.Logf("verify: %v, unexpected error: %s\n", verify, err)
+			t.FailNow()
+		}
+
+		if m.Directed() || m.E() != g.E() || m.String() != g.String() {
+			t.Logf("verify: %v, mapped graph differs from the original\n", verify)
+			t.Fail()
+		}
+		m.Close()
+
+		m, err = OpenMapped(write_binary_file(t, dir, d, false), verify)
+		if err != nil || !m.Directed() || m.String() != d.String() {
+			t.Logf("verify: %v, mapped digraph differs from the original, error: %v\n", verify, err)
+			t.Fail()
+		}
+		m.Close()
+	}
+}
+
+func TestMappedGraphCorruption(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "mapped-graph")
+	defer os.RemoveAll(dir)
+
+	fname := write_binary_file(t, dir, create_bench_graph(32), true)
+	good, _ := ioutil.ReadFile(fname)
+
+	test_cases := []struct {
+		data   []byte
+		verify bool
+		reason string
+	}{
+		{good[:10], false, "truncated"},
+		{good[:len(good)-1], false, "truncated"},
+		{append(append([]byte(nil), good...), 0), false, "trailing data"},
+		{append([]byte("XXXX"), good[4:]...), false, "bad magic"},
+		{corrupt_copy(good, binary_header_size+4*20, 0xff), true, "offsets decrease"},
+		{corrupt_copy(good, binary_header_size+4*33+2, 1), true, "out of range"},
+		{corrupt_copy(good, binary_header_size+4*33, 5), true, "checksum mismatch"},
+	}
+
+	for i, tc := range test_cases {
+		ioutil.WriteFile(fname, tc.data, 0644)
+
+		_, err := OpenMapped(fname, tc.verify)
+		if err == nil || !strings.Contains(err.Error(), tc.reason) {
+			t.Logf("test-case: %d, expected: '%s', got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+}
+
+func corrupt_copy(data []byte, at int, val byte) []byte {
+	bad := append([]byte(nil), data...)
+	bad[at] = val
+
+	return bad
+}
@@ -0,0 +1,379 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements extracting subgraphs (induced by a set of
+// vertices, or by filtering edges), and set operations (union,
+// intersection, difference, complement) over graphs with the same
+// vertex set.
+//
+// graphs here may be multigraphs, so edges are treated as multisets
+// i.e. the multiplicity of an edge in the union is the larger of its
+// multiplicities in the two graphs, in the intersection the smaller
+// one, and in the difference whatever remains after subtraction. the
+// complement is always a simple graph. vertex and edge attributes
+// (if any) go along with the vertices and edges they belong to.
+//
+package graph
+
+import (
+	"fmt"
+)
+
+//
+// mapping between the vertices of a graph, and those of a subgraph
+// extracted from it
+//
+type SubgraphMap struct {
+	to_new []int32 // graph vertex -> subgraph vertex (-1 if left out)
+	to_old []int32 // subgraph vertex -> graph vertex
+}
+
+// number of vertices in the subgraph
+func (m *SubgraphMap) V() int32 { return int32(len(m.to_old)) }
+
+// subgraph vertex for the graph vertex 'old', if it is in the subgraph
+func (m *SubgraphMap) New(old int32) (v int32, ok bool) {
+	if old < 0 || old >= int32(len(m.to_new)) || m.to_new[old] < 0 {
+		return -1, false
+	}
+	return m.to_new[old], true
+}
+
+// graph vertex for the subgraph vertex 'v'
+func (m *SubgraphMap) Old(v int32) int32 { return m.to_old[v] }
+
+//
+// this function returns the subgraph induced by 'vertices' i.e. the
+// vertices themselves, and all edges between them. subgraph vertices
+// are numbered in the order in which they are listed. an error is
+// returned for out of range, or repeated vertices.
+//
+func (G *Graph) InducedSubgraph(vertices []int32) (sub *Graph, vmap *SubgraphMap, err error) {
+	if vmap, err = new_subgraph_map(G.V(), vertices); err != nil {
+		return
+	}
+
+	sub = New(vmap.V())
+	induce_edges(G, sub, vmap, false)
+	sub.attrs = G.attrs.remap(vmap.to_new)
+
+	return
+}
+
+func (G *Digraph) InducedSubgraph(vertices []int32) (sub *Digraph, vmap *SubgraphMap, err error) {
+	if vmap, err = new_subgraph_map(G.V(), vertices); err != nil {
+		return
+	}
+
+	sub = CreateDigraph(vmap.V())
+	induce_edges(G, sub, vmap, true)
+	sub.attrs = G.attrs.remap(vmap.to_new)
+
+	return
+}
+
+//
+// this function returns the subgraph induced by the vertices for
+// which 'keep' returns true, which retain their relative order
+//
+func (G *Graph) FilterVertices(keep func(v int32) bool) (sub *Graph, vmap *SubgraphMap) {
+	sub, vmap, _ = G.InducedSubgraph(filter_vertices(G, keep))
+	return
+}
+
+func (G *Digraph) FilterVertices(keep func(v int32) bool) (sub *Digraph, vmap *SubgraphMap) {
+	sub, vmap, _ = G.InducedSubgraph(filter_vertices(G, keep))
+	return
+}
+
+//
+// this function returns a graph with the same vertices, and just
+// those edges for which 'keep' returns true. for undirected graphs,
+// 'keep' is invoked once per edge, with v <= w.
+//
+func (G *Graph) FilterEdges(keep func(v, w int32) bool) (sub *Graph) {
+	sub = New(G.V())
+	filter_edges(G, sub, false, keep)
+	sub.attrs = G.attrs.for_edges_of(sub)
+
+	return
+}
+
+func (G *Digraph) FilterEdges(keep func(v, w int32) bool) (sub *Digraph) {
+	sub = CreateDigraph(G.V())
+	filter_edges(G, sub, true, keep)
+	sub.attrs = G.attrs.for_edges_of(sub)
+
+	return
+}
+
+//
+// union, intersection, and difference of two graphs with the same
+// number of vertices. vertex attributes come from 'G', and edge
+// attributes from 'G' (or 'H' for edges only in 'H').
+//
+func (G *Graph) Union(H *Graph) (*Graph, error)        { return G.set_op(H, max_int32) }
+func (G *Graph) Intersection(H *Graph) (*Graph, error) { return G.set_op(H, min_int32) }
+func (G *Graph) Difference(H *Graph) (*Graph, error)   { return G.set_op(H, sub_int32) }
+
+func (G *Digraph) Union(H *Digraph) (*Digraph, error)        { return G.set_op(H, max_int32) }
+func (G *Digraph) Intersection(H *Digraph) (*Digraph, error) { return G.set_op(H, min_int32) }
+func (G *Digraph) Difference(H *Digraph) (*Digraph, error)   { return G.set_op(H, sub_int32) }
+
+//
+// this function returns the complement of the graph i.e. a simple
+// graph over the same vertices, with an edge v-w (v != w) wherever
+// the graph doesn't have one. vertex attributes are retained.
+//
+func (G *Graph) Complement() (C *Graph) {
+	C = New(G.V())
+	complement_edges(G, C, false)
+	C.attrs = G.attrs.for_edges_of(C)
+
+	return
+}
+
+//
+// this function returns the complement of the digraph i.e. a simple
+// digraph over the same vertices, with an edge v -> w (v != w)
+// wherever the digraph doesn't have one. vertex attributes are
+// retained.
+//
+func (G *Digraph) Complement() (C *Digraph) {
+	C = CreateDigraph(G.V())
+	complement_edges(G, C, true)
+	C.attrs = G.attrs.for_edges_of(C)
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// graphs that can be built edge by edge
+type graph_builder_t interface {
+	GraphOps
+	AddEdge(v, w int32) error
+}
+
+func new_subgraph_map(V int32, vertices []int32) (vmap *SubgraphMap, err error) {
+	vmap = &SubgraphMap{
+		to_new: make([]int32, V),
+		to_old: make([]int32, len(vertices)),
+	}
+
+	for i := range vmap.to_new {
+		vmap.to_new[i] = -1
+	}
+
+	for i, v := range vertices {
+		switch {
+		case v < 0 || v >= V:
+			return nil, fmt.Errorf("bogus vertex: %d", v)
+		case vmap.to_new[v] >= 0:
+			return nil, fmt.Errorf("repeated vertex: %d", v)
+		}
+
+		vmap.to_new[v] = int32(i)
+		vmap.to_old[i] = v
+	}
+
+	return
+}
+
+//
+// add edges of 'G' among the vertices of 'vmap' to 'sub'. only the
+// adjacency lists of these vertices are looked at, so that small
+// subgraphs of large graphs are cheap. for undirected graphs, each
+// edge is added once, from the lower numbered subgraph vertex, and
+// every other self-loop entry is skipped.
+//
+func induce_edges(G GraphOps, sub graph_builder_t, vmap *SubgraphMap, directed bool) {
+	for nv, v := range vmap.to_old {
+		self_loops := 0
+
+		for _, w := range G.Adj(v) {
+			nw := vmap.to_new[w]
+
+			switch {
+			case nw < 0:
+				continue
+			case !directed && int32(nv) > nw:
+				continue
+			case !directed && int32(nv) == nw:
+				if self_loops += 1; self_loops%2 == 0 {
+					continue
+				}
+			}
+
+			sub.AddEdge(int32(nv), nw)
+		}
+	}
+}
+
+func filter_vertices(G GraphOps, keep func(v int32) bool) (vertices []int32) {
+	for v := int32(0); v < G.V(); v++ {
+		if keep(v) {
+			vertices = append(vertices, v)
+		}
+	}
+
+	return
+}
+
+func filter_edges(G GraphOps, sub graph_builder_t, directed bool, keep func(v, w int32) bool) {
+	for_each_edge(G, directed, func(v, w int32) {
+		if keep(v, w) {
+			sub.AddEdge(v, w)
+		}
+	})
+}
+
+func complement_edges(G GraphOps, C graph_builder_t, directed bool) {
+	adjacent := make([]bool, G.V())
+
+	for v := int32(0); v < G.V(); v++ {
+		for _, w := range G.Adj(v) {
+			adjacent[w] = true
+		}
+
+		for w := int32(0); w < G.V(); w++ {
+			if w != v && !adjacent[w] && (directed || v < w) {
+				C.AddEdge(v, w)
+			}
+		}
+
+		for _, w := range G.Adj(v) {
+			adjacent[w] = false
+		}
+	}
+}
+
+//
+// build 'R' from the edges of 'G' and 'H', with 'op' deciding the
+// multiplicity of each edge in 'R' from its multiplicity in 'G' and
+// 'H'. neighbors of each vertex are counted in a dense array which is
+// reset after each vertex, making this O(V + E).
+//
+func edge_set_op(G, H GraphOps, R graph_builder_t, directed bool, op func(x, y int32) int32) (err error) {
+	if G.V() != H.V() {
+		return fmt.Errorf("vertex count mismatch: %d, %d", G.V(), H.V())
+	}
+
+	in_g := make([]int32, G.V())
+	in_h := make([]int32, G.V())
+	var touched []int32
+
+	for v := int32(0); v < G.V(); v++ {
+		touched = touched[:0]
+
+		count := func(adj []int32, counts []int32) {
+			for _, w := range adj {
+				if !directed && w < v {
+					continue
+				}
+				if in_g[w] == 0 && in_h[w] == 0 {
+					touched = append(touched, w)
+				}
+				counts[w] += 1
+			}
+		}
+		count(G.Adj(v), in_g)
+		count(H.Adj(v), in_h)
+
+		for _, w := range touched {
+			x, y := in_g[w], in_h[w]
+
+			// self-loops show up twice in undirected graphs
+			if !directed && v == w {
+				x, y = x/2, y/2
+			}
+
+			for n := op(x, y); n > 0; n-- {
+				R.AddEdge(v, w)
+			}
+			in_g[w], in_h[w] = 0, 0
+		}
+	}
+
+	return
+}
+
+func (G *Graph) set_op(H *Graph, op func(x, y int32) int32) (R *Graph, err error) {
+	R = New(G.V())
+	if err = edge_set_op(G, H, R, false, op); err != nil {
+		return nil, err
+	}
+	R.attrs = merge_attributes(G.attrs, H.attrs, R, false)
+
+	return
+}
+
+func (G *Digraph) set_op(H *Digraph, op func(x, y int32) int32) (R *Digraph, err error) {
+	R = CreateDigraph(G.V())
+	if err = edge_set_op(G, H, R, true, op); err != nil {
+		return nil, err
+	}
+	R.attrs = merge_attributes(G.attrs, H.attrs, R, true)
+
+	return
+}
+
+//
+// attributes for the result of a set operation on graphs having
+// attributes 'g', and 'h'
+//
+func merge_attributes(g, h *Attributes, R GraphOps, directed bool) (m *Attributes) {
+	if g == nil && h == nil {
+		return nil
+	}
+
+	if m = g.for_edges_of(R); m == nil {
+		m = new_attributes(directed)
+	}
+
+	if h != nil {
+		for k, attrs := range h.edge {
+			if _, ok := m.edge[k]; !ok && has_edge(R, k.v, k.w, directed) {
+				m.edge[k] = clone_attrs(attrs)
+			}
+		}
+	}
+
+	return
+}
+
+func max_int32(x, y int32) int32 {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func sub_int32(x, y int32) int32 { return x - y }
@@ -0,0 +1,222 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// graphs
+//
+// this file implements the testing routine for subgraphs, and set
+// operations on graphs
+//
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInducedSubgraph(t *testing.T) {
+	g := create_test_graph(&graphs[0].graph_defn)
+	g.AddEdge(5, 5)
+	g.Attributes().SetEdge(3, 5, "w", NumberAttr(7))
+
+	sub, vmap, err := g.InducedSubgraph([]int32{5, 4, 3, 0})
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	// 0-5, 5-4, 5-3, 4-3, and 5-5
+	want := create_test_graph(&graph_definition{4, 5, [][2]int32{{3, 0}, {0, 1}, {0, 2}, {1, 2}, {0, 0}}})
+	if !cmp_graph(sub, want) {
+		t.Logf("expected:\n%s\ngot:\n%s\n", want, sub)
+		t.Fail()
+	}
+
+	if v, ok := vmap.New(3); !ok || v != 2 || vmap.Old(3) != 0 || vmap.V() != 4 {
+		t.Logf("unexpected vertex mapping: %v\n", vmap)
+		t.Fail()
+	}
+	if _, ok := vmap.New(6); ok {
+		t.Logf("vertex left out of the subgraph is mapped\n")
+		t.Fail()
+	}
+
+	if val, ok := sub.Attributes().Edge(0, 2, "w"); !ok || val.Num != 7 {
+		t.Logf("edge attribute didn't follow the edge into the subgraph\n")
+		t.Fail()
+	}
+
+	for _, vertices := range [][]int32{{0, 13}, {1, 2, 1}, {-1}} {
+		if _, _, err = g.InducedSubgraph(vertices); err == nil {
+			t.Logf("vertices: %v, expected an error\n", vertices)
+			t.Fail()
+		}
+	}
+
+	// vertices 7, 8, and 9 -> 0, 1, 2
+	d := CreateDigraph(10)
+	d.AddEdge(7, 8)
+	d.AddEdge(8, 9)
+	d.AddEdge(9, 7)
+	d.AddEdge(1, 7)
+
+	dsub, dmap := d.FilterVertices(func(v int32) bool { return v >= 7 })
+	if dsub.V() != 3 || dsub.E() != 3 || !dsub.HasEdge(2, 0) || dmap.Old(0) != 7 {
+		t.Logf("unexpected filtered digraph:\n%s\n", dsub)
+		t.Fail()
+	}
+}
+
+func TestFilterEdges(t *testing.T) {
+	g := create_test_graph(&graphs[0].graph_defn)
+	g.Attributes().SetEdge(0, 5, "w", NumberAttr(1))
+	g.Attributes().SetEdge(0, 6, "w", NumberAttr(2))
+
+	// edges out of 0 are gone
+	sub := g.FilterEdges(func(v, w int32) bool { return v != 0 && w != 0 })
+	if sub.V() != g.V() || sub.E() != g.E()-4 || len(sub.Adj(0)) != 0 {
+		t.Logf("unexpected filtered graph:\n%s\n", sub)
+		t.Fail()
+	}
+
+	if _, ok := sub.Attributes().Edge(0, 5, "w"); ok {
+		t.Logf("filtered out edge kept its attributes\n")
+		t.Fail()
+	}
+	if _, ok := g.Attributes().Edge(0, 5, "w"); !ok {
+		t.Logf("filtering modified attributes of the original graph\n")
+		t.Fail()
+	}
+}
+
+func TestGraphSetOperations(t *testing.T) {
+	// g: 0-1, 0-1, 1-2, 2-2
+	g := New(4)
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 2)
+
+	// h: 1-0, 2-3, 2-2
+	h := New(4)
+	h.AddEdge(1, 0)
+	h.AddEdge(2, 3)
+	h.AddEdge(2, 2)
+
+	test_data := []struct {
+		what string
+		op   func(*Graph) (*Graph, error)
+		want [][2]int32
+	}{
+		{"union", g.Union, [][2]int32{{0, 1}, {0, 1}, {1, 2}, {2, 2}, {2, 3}}},
+		{"intersection", g.Intersection, [][2]int32{{0, 1}, {2, 2}}},
+		{"difference", g.Difference, [][2]int32{{0, 1}, {1, 2}}},
+	}
+
+	for _, td := range test_data {
+		got, err := td.op(h)
+		want := create_test_graph(&graph_definition{4, int32(len(td.want)), td.want})
+
+		if err != nil || !cmp_graph(got, want) {
+			t.Logf("%s: error: %v, expected:\n%s\ngot:\n%s\n", td.what, err, want, got)
+			t.Fail()
+		}
+	}
+
+	if _, err := g.Union(New(3)); err == nil {
+		t.Logf("expected an error for mismatched vertex counts\n")
+		t.Fail()
+	}
+
+	// 0-2, 0-3, 1-3, 2-3
+	want := create_test_graph(&graph_definition{4, 4, [][2]int32{{0, 2}, {0, 3}, {1, 3}, {2, 3}}})
+	if c := g.Complement(); !cmp_graph(c, want) {
+		t.Logf("complement: expected:\n%s\ngot:\n%s\n", want, c)
+		t.Fail()
+	}
+}
+
+func TestDigraphSetOperations(t *testing.T) {
+	g := CreateDigraph(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.Attributes().SetEdge(0, 1, "from", StringAttr("g"))
+
+	h := CreateDigraph(3)
+	h.AddEdge(1, 0)
+	h.AddEdge(1, 2)
+	h.Attributes().SetEdge(1, 0, "from", StringAttr("h"))
+	h.Attributes().SetEdge(1, 2, "from", StringAttr("h"))
+
+	u, _ := g.Union(h)
+	i, _ := g.Intersection(h)
+	d, _ := g.Difference(h)
+	c := g.Complement()
+
+	if u.E() != 3 || !u.HasEdge(1, 0) || !u.HasEdge(0, 1) || i.E() != 1 || !i.HasEdge(1, 2) || d.E() != 1 || !d.HasEdge(0, 1) {
+		t.Logf("unexpected results:\nunion:\n%s\nintersection:\n%s\ndifference:\n%s\n", u, i, d)
+		t.Fail()
+	}
+
+	// 0 -> 2, 1 -> 0, 2 -> 0, 2 -> 1
+	if c.E() != 4 || c.HasEdge(0, 1) || !c.HasEdge(1, 0) || c.HasEdge(0, 0) {
+		t.Logf("unexpected complement:\n%s\n", c)
+		t.Fail()
+	}
+
+	// edge attributes from 'g' first, and then from 'h'
+	for _, td := range []struct {
+		v, w int32
+		from string
+	}{{0, 1, "g"}, {1, 0, "h"}, {1, 2, "h"}} {
+		if val, _ := u.Attributes().Edge(td.v, td.w, "from"); val.Str != td.from {
+			t.Logf("union: edge %d -> %d, expected attribute from: %s, got: %v\n", td.v, td.w, td.from, val)
+			t.Fail()
+		}
+	}
+
+	if _, ok := d.Attributes().Edge(1, 2, "from"); ok {
+		t.Logf("difference: edge attribute survived its edge\n")
+		t.Fail()
+	}
+}
+
+func ExampleGraph_InducedSubgraph() {
+	g := New(5)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+
+	sub, vmap, _ := g.InducedSubgraph([]int32{3, 2, 1})
+	for v := int32(0); v < sub.V(); v++ {
+		fmt.Println(vmap.Old(v), "->", v, sub.Adj(v))
+	}
+
+	// Output:
+	// 3 -> 0 [1]
+	// 2 -> 1 [0 2]
+	// 1 -> 2 [1]
+}
@@ -0,0 +1,572 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements a parser for a subset of graphviz's dot
+// language. supported are:
+//
+//     - 'strict', 'graph' and 'digraph' headers with an optional name
+//     - node statements:  a [attr=value, ...]
+//     - edge statements:  a -> b -> c [attr=value, ...]
+//     - attribute statements (graph/node/edge [...]), and 'id = id'
+//     - quoted identifiers, and //, /* */ and # comments
+//
+// as in graphviz, 'strict' graphs have at most one edge between any
+// pair of vertices, with repeated edges being dropped. all attributes
+// are accepted, but ignored. subgraphs and ports are not supported.
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// kinds of tokens in the dot language
+type dot_token_kind_t int
+
+const (
+	dot_tok_eof dot_token_kind_t = iota
+	dot_tok_id
+	dot_tok_edge_op
+	dot_tok_punct
+)
+
+type dot_token_t struct {
+	kind   dot_token_kind_t
+	text   string
+	quoted bool
+	line   int
+}
+
+//
+// result of parsing a dot graph. vertex names are in the order of
+// their first appearance
+//
+type dot_graph_t struct {
+	strict   bool
+	directed bool
+	names    []string
+	edges    [][2]string
+}
+
+// a dot lexer+parser working on a reader
+type dot_parser_t struct {
+	src   *bufio.Reader
+	line  int
+	ahead *dot_token_t
+	seen  map[string]bool
+	graph dot_graph_t
+}
+
+//
+// this function parses a single graph in the dot language from a
+// given input source.
+//
+func parse_dot(src *bufio.Reader) (g *dot_graph_t, err error) {
+	p := &dot_parser_t{
+		src:  src,
+		line: 1,
+		seen: make(map[string]bool),
+	}
+
+	if err = p.parse_graph(); err != nil {
+		return
+	}
+
+	g = &p.graph
+	return
+}
+
+//
+// private parser stuff
+//
+
+// graph: ['strict'] ('graph' | 'digraph') [ID] '{' stmt_list '}'
+func (p *dot_parser_t) parse_graph() (err error) {
+	var tok dot_token_t
+
+	if tok, err = p.next(); err != nil {
+		return
+	}
+
+	if p.is_keyword(tok, "strict") {
+		p.graph.strict = true
+		if tok, err = p.next(); err != nil {
+			return
+		}
+	}
+
+	switch {
+	case p.is_keyword(tok, "graph"):
+		p.graph.directed = false
+	case p.is_keyword(tok, "digraph"):
+		p.graph.directed = true
+	default:
+		return p.errorf(tok, "expected 'graph' or 'digraph', found '%s'", tok.text)
+	}
+
+	// optional graph name
+	if tok, err = p.next(); err != nil {
+		return
+	}
+	if tok.kind == dot_tok_id {
+		if tok, err = p.next(); err != nil {
+			return
+		}
+	}
+
+	if !p.is_punct(tok, "{") {
+		return p.errorf(tok, "expected '{', found '%s'", tok.text)
+	}
+
+	if err = p.parse_stmt_list(); err != nil {
+		return
+	}
+
+	// nothing but comments may follow
+	if tok, err = p.next(); err != nil {
+		return
+	}
+	if tok.kind != dot_tok_eof {
+		return p.errorf(tok, "unexpected '%s' after graph", tok.text)
+	}
+
+	return
+}
+
+// stmt_list: (stmt [';'])* '}'
+func (p *dot_parser_t) parse_stmt_list() (err error) {
+	for {
+		var tok dot_token_t
+
+		if tok, err = p.next(); err != nil {
+			return
+		}
+
+		switch {
+		case tok.kind == dot_tok_eof:
+			return p.errorf(tok, "unterminated graph, expected '}'")
+
+		case p.is_punct(tok, "}"):
+			return
+
+		case p.is_punct(tok, ";"):
+			continue
+
+		case p.is_keyword(tok, "subgraph") || p.is_punct(tok, "{"):
+			return p.errorf(tok, "subgraphs are not supported")
+
+		case p.is_keyword(tok, "graph") || p.is_keyword(tok, "node") || p.is_keyword(tok, "edge"):
+			err = p.parse_attr_lists(true)
+
+		case tok.kind == dot_tok_id:
+			err = p.parse_node_or_edge_stmt(tok)
+
+		default:
+			return p.errorf(tok, "unexpected '%s'", tok.text)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+//
+// node_stmt: ID [attr_list]
+// edge_stmt: ID (edge_op ID)+ [attr_list]
+// or else:   ID '=' ID
+//
+func (p *dot_parser_t) parse_node_or_edge_stmt(first dot_token_t) (err error) {
+	var tok dot_token_t
+
+	if tok, err = p.peek(); err != nil {
+		return
+	}
+
+	// graph attribute
+	if p.is_punct(tok, "=") {
+		p.next()
+		if tok, err = p.next(); err != nil {
+			return
+		}
+		if tok.kind != dot_tok_id {
+			return p.errorf(tok, "expected value after '=', found '%s'", tok.text)
+		}
+		return
+	}
+
+	p.add_vertex(first.text)
+
+	// edge chain
+	for prev := first.text; tok.kind == dot_tok_edge_op; {
+		p.next()
+
+		if p.graph.directed != (tok.text == "->") {
+			return p.errorf(tok, "edge operator '%s' in a %s", tok.text, p.graph_kind())
+		}
+
+		var dst dot_token_t
+		if dst, err = p.next(); err != nil {
+			return
+		}
+		if dst.kind != dot_tok_id {
+			if p.is_keyword(dst, "subgraph") || p.is_punct(dst, "{") {
+				return p.errorf(dst, "subgraphs are not supported")
+			}
+			return p.errorf(dst, "expected vertex after '%s', found '%s'", tok.text, dst.text)
+		}
+
+		p.add_vertex(dst.text)
+		p.graph.edges = append(p.graph.edges, [2]string{prev, dst.text})
+		prev = dst.text
+
+		if tok, err = p.peek(); err != nil {
+			return
+		}
+	}
+
+	if p.is_punct(tok, ":") {
+		return p.errorf(tok, "ports are not supported")
+	}
+
+	return p.parse_attr_lists(false)
+}
+
+//
+// attr_list: ('[' [a_list] ']')+, where a_list is a sequence of
+// 'ID = ID' separated by ',' or ';'. atleast one list is expected
+// when 'required' is true.
+//
+func (p *dot_parser_t) parse_attr_lists(required bool) (err error) {
+	var tok dot_token_t
+
+	for {
+		if tok, err = p.peek(); err != nil {
+			return
+		}
+
+		if !p.is_punct(tok, "[") {
+			if required {
+				return p.errorf(tok, "expected '[', found '%s'", tok.text)
+			}
+			return
+		}
+		p.next()
+		required = false
+
+	attr_list:
+		for {
+			if tok, err = p.next(); err != nil {
+				return
+			}
+
+			switch {
+			case p.is_punct(tok, "]"):
+				break attr_list
+
+			case p.is_punct(tok, ",") || p.is_punct(tok, ";"):
+				continue
+
+			case tok.kind == dot_tok_id:
+				var eq, val dot_token_t
+
+				if eq, err = p.next(); err != nil {
+					return
+				}
+				if !p.is_punct(eq, "=") {
+					return p.errorf(eq, "expected '=' after attribute '%s'", tok.text)
+				}
+
+				if val, err = p.next(); err != nil {
+					return
+				}
+				if val.kind != dot_tok_id {
+					return p.errorf(val, "expected value for attribute '%s'", tok.text)
+				}
+
+			default:
+				return p.errorf(tok, "unterminated attribute list")
+			}
+		}
+	}
+}
+
+// record a vertex, the first time it is seen
+func (p *dot_parser_t) add_vertex(name string) {
+	if p.seen[name] {
+		return
+	}
+
+	p.seen[name] = true
+	p.graph.names = append(p.graph.names, name)
+}
+
+func (p *dot_parser_t) graph_kind() string {
+	if p.graph.directed {
+		return "digraph"
+	}
+	return "graph"
+}
+
+// keywords are case-insensitive, and never quoted
+func (p *dot_parser_t) is_keyword(tok dot_token_t, kw string) bool {
+	return tok.kind == dot_tok_id && !tok.quoted && strings.EqualFold(tok.text, kw)
+}
+
+func (p *dot_parser_t) is_punct(tok dot_token_t, punct string) bool {
+	return tok.kind == dot_tok_punct && tok.text == punct
+}
+
+// a parse error at the token's line
+func (p *dot_parser_t) errorf(tok dot_token_t, format string, args ...interface{}) error {
+	return fmt.Errorf("dot: line %d: %s", tok.line, fmt.Sprintf(format, args...))
+}
+
+//
+// private lexer stuff
+//
+
+// return the next token without consuming it
+func (p *dot_parser_t) peek() (tok dot_token_t, err error) {
+	if p.ahead == nil {
+		if tok, err = p.lex(); err != nil {
+			return
+		}
+		p.ahead = &tok
+	}
+
+	tok = *p.ahead
+	return
+}
+
+// consume and return the next token
+func (p *dot_parser_t) next() (tok dot_token_t, err error) {
+	if p.ahead != nil {
+		tok, p.ahead = *p.ahead, nil
+		return
+	}
+
+	return p.lex()
+}
+
+// read a single character, keeping track of line numbers
+func (p *dot_parser_t) read() (c rune, err error) {
+	if c, _, err = p.src.ReadRune(); err == nil && c == '\n' {
+		p.line += 1
+	}
+
+	return
+}
+
+func (p *dot_parser_t) unread(c rune) {
+	p.src.UnreadRune()
+	if c == '\n' {
+		p.line -= 1
+	}
+}
+
+// identifier characters i.e. letters, digits, '_', '.' and non-ascii
+func is_dot_id_char(c rune) bool {
+	return c == '_' || c == '.' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// scan the next token from the input
+func (p *dot_parser_t) lex() (tok dot_token_t, err error) {
+	var c rune
+
+	// skip whitespace and comments
+	for {
+		if c, err = p.read(); err == io.EOF {
+			tok, err = dot_token_t{kind: dot_tok_eof, text: "EOF", line: p.line}, nil
+			return
+		} else if err != nil {
+			return
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+
+		case c == '#':
+			p.skip_line()
+			continue
+
+		case c == '/':
+			var c2 rune
+			c2, err = p.read()
+			switch {
+			case err == nil && c2 == '/':
+				p.skip_line()
+				continue
+			case err == nil && c2 == '*':
+				if err = p.skip_block_comment(); err != nil {
+					return
+				}
+				continue
+			}
+			err = fmt.Errorf("dot: line %d: unexpected character '/'", p.line)
+			return
+		}
+
+		break
+	}
+
+	tok.line = p.line
+
+	switch {
+	case c == '"':
+		tok.kind, tok.quoted = dot_tok_id, true
+		tok.text, err = p.lex_quoted()
+
+	case c == '-':
+		var c2 rune
+		if c2, err = p.read(); err != nil {
+			err = fmt.Errorf("dot: line %d: unexpected character '-'", tok.line)
+			return
+		}
+
+		if c2 == '-' || c2 == '>' {
+			tok.kind, tok.text = dot_tok_edge_op, "-"+string(c2)
+			return
+		}
+
+		// negative numeral
+		p.unread(c2)
+		tok.kind = dot_tok_id
+		tok.text = "-" + p.lex_plain()
+
+	case is_dot_id_char(c):
+		p.unread(c)
+		tok.kind = dot_tok_id
+		tok.text = p.lex_plain()
+
+	case strings.ContainsRune("{}[]=;,:", c):
+		tok.kind, tok.text = dot_tok_punct, string(c)
+
+	default:
+		err = fmt.Errorf("dot: line %d: unexpected character '%c'", tok.line, c)
+	}
+
+	return
+}
+
+// a run of identifier characters
+func (p *dot_parser_t) lex_plain() string {
+	var sb strings.Builder
+
+	for {
+		c, err := p.read()
+		if err != nil {
+			break
+		}
+
+		if !is_dot_id_char(c) {
+			p.unread(c)
+			break
+		}
+		sb.WriteRune(c)
+	}
+
+	return sb.String()
+}
+
+// a double-quoted string, where '\"' stands for '"'
+func (p *dot_parser_t) lex_quoted() (str string, err error) {
+	var sb strings.Builder
+	start := p.line
+
+	for {
+		var c rune
+
+		if c, err = p.read(); err != nil {
+			err = fmt.Errorf("dot: line %d: unterminated string", start)
+			return
+		}
+
+		switch c {
+		case '"':
+			str = sb.String()
+			return
+
+		case '\\':
+			var c2 rune
+			if c2, err = p.read(); err != nil {
+				err = fmt.Errorf("dot: line %d: unterminated string", start)
+				return
+			}
+
+			switch c2 {
+			case '"', '\\':
+				sb.WriteRune(c2)
+			case '\n':
+				// line continuation
+			default:
+				sb.WriteRune('\\')
+				sb.WriteRune(c2)
+			}
+
+		default:
+			sb.WriteRune(c)
+		}
+	}
+}
+
+// skip rest of the current line
+func (p *dot_parser_t) skip_line() {
+	for {
+		c, err := p.read()
+		if err != nil || c == '\n' {
+			return
+		}
+	}
+}
+
+// skip a '/* ... */' comment, the opening '/*' has been consumed
+func (p *dot_parser_t) skip_block_comment() (err error) {
+	var prev rune
+	start := p.line
+
+	for {
+		var c rune
+
+		if c, err = p.read(); err != nil {
+			err = fmt.Errorf("dot: line %d: unterminated comment", start)
+			return
+		}
+
+		if prev == '*' && c == '/' {
+			return
+		}
+		prev = c
+	}
+}
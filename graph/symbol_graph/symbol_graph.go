@@ -32,30 +32,26 @@ package symbol_graph
 
 import (
 	"bufio"
-	"fmt"
 	"github.com/anupamk/common-utilz/graph"
-	"os"
+	"io"
 )
 
+//
+// a symbol-graph keyed by strings. most of its methods come from
+// SymbolGraphOf
+//
 type SymbolGraph struct {
-	sym_table map[string]int32 // vertex-name -> vertex-index
-	keys      []string         // vertex-index -> vertex-name
-	sym_graph *graph.Graph     // resultant graph
+	SymbolGraphOf[string]
 }
 
 type string_slice_t []string
 
 //
-// this function returns the stringified representation of the
-// symbol-graph
+// create a new, empty symbol-graph, to be populated with AddVertex(...)
+// and AddEdge(...)
 //
-func (sg *SymbolGraph) String() (retval string) {
-
-	retval += fmt.Sprintf("symbol-table: %v\n", sg.sym_table)
-	retval += fmt.Sprintf("reverse-index: %v\n", sg.keys)
-	retval += fmt.Sprintf("graph\n%s", sg.sym_graph)
-
-	return
+func New() *SymbolGraph {
+	return &SymbolGraph{*NewOf[string]()}
 }
 
 //
@@ -65,11 +61,9 @@ func (sg *SymbolGraph) String() (retval string) {
 func LoadFromReader(src *bufio.Reader, sep string) (sg *SymbolGraph, sg_err error) {
 	symtab, revindex, edge_list := load_symtab_revindex_from_reader(src, sep)
 
-	sg = &SymbolGraph{
-		sym_table: make(map[string]int32),
-		keys:      make([]string, len(revindex)),
-		sym_graph: nil,
-	}
+	sg = &SymbolGraph{}
+	sg.sym_table = make(map[string]int32)
+	sg.keys = make([]string, len(revindex))
 
 	// copy the result
 	for k, v := range symtab {
@@ -99,9 +93,9 @@ func LoadFromReader(src *bufio.Reader, sep string) (sg *SymbolGraph, sg_err erro
 // file identified by 'fname'
 //
 func LoadFromFile(fname string, sep string) (sg *SymbolGraph, err error) {
-	var f *os.File
+	var f io.ReadCloser
 
-	if f, err = os.Open(fname); err != nil {
+	if f, err = graph.OpenFile(fname); err != nil {
 		return
 	}
 	defer f.Close()
@@ -113,46 +107,3 @@ func LoadFromFile(fname string, sep string) (sg *SymbolGraph, err error) {
 
 	return
 }
-
-//
-// this function returns true if the symbol-graph contains 'str' as a
-// vertex
-//
-func (sg *SymbolGraph) Contains(str string) bool {
-	_, ok := sg.sym_table[str]
-	return ok
-}
-
-//
-// this function returns the index associated with a given key, if the
-// key doesn't exist, it panics. thus clients, are expected to ensure
-// that the key is available before coming here.
-//
-func (sg *SymbolGraph) Index(key string) (idx int32) {
-	var ok bool
-
-	if idx, ok = sg.sym_table[key]; !ok {
-		err := fmt.Errorf("key: '%s' doesn't exist", key)
-		panic(err)
-	}
-
-	return
-}
-
-//
-// this function returns the name associated with a given vertex. if
-// the vertex is invalid, an error is flagged
-func (sg *SymbolGraph) Name(vertex_id int32) (name string, err error) {
-	if vertex_id > int32(len(sg.keys)) {
-		err = fmt.Errorf("vertex: '%d' doesn't exist", vertex_id)
-		return
-	}
-
-	name = sg.keys[vertex_id]
-	return
-}
-
-//
-// this function returns a pointer to the underlying graph in the
-// symbol graph.
-func (sg *SymbolGraph) G() (g *graph.Graph) { return sg.sym_graph }
@@ -0,0 +1,183 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements key based wrappers over the traversal and
+// algorithms packages, so that clients needn't translate keys to
+// vertex ids and back by hand.
+//
+package symbol_graph
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/graph/algorithms"
+	"github.com/anupamk/common-utilz/traversal"
+)
+
+//
+// this function returns keys of the vertices on a shortest path from
+// 'from' to 'to', both inclusive. an *ErrUnknownVertex is returned if
+// either of the keys doesn't exist, and an error if there is no path
+// between them.
+//
+func (sg *SymbolGraphOf[K]) ShortestPath(from, to K) (path []K, err error) {
+	return sg.shortest_path(sg.sym_graph, from, to)
+}
+
+//
+// same as above, but for digraphs. searching from both ends needs the
+// reversed digraph, so this does a plain breadth-first search from
+// 'from' instead.
+//
+func (sg *SymbolDigraphOf[K]) ShortestPath(from, to K) (path []K, err error) {
+	ids, err := sg.IndexAll([]K{from, to})
+	if err != nil {
+		return
+	}
+
+	if ids[0] == ids[1] {
+		return []K{from}, nil
+	}
+
+	if ids, err = algorithms.BFSPath(sg.sym_graph, ids[0]).PathTo(ids[1]); err != nil {
+		err = fmt.Errorf("no path from: '%v' to: '%v'", from, to)
+		return
+	}
+
+	return sg.NameAll(ids)
+}
+
+//
+// this function returns keys of the vertices adjacent to 'key', in
+// adjacency order
+//
+func (sg *SymbolGraphOf[K]) Neighbors(key K) (adj []K, err error) {
+	return sg.neighbors(sg.sym_graph, key)
+}
+
+func (sg *SymbolDigraphOf[K]) Neighbors(key K) (adj []K, err error) {
+	return sg.neighbors(sg.sym_graph, key)
+}
+
+//
+// this function returns keys of all the vertices in the connected
+// component containing 'key', including 'key' itself, in vertex id
+// order
+//
+func (sg *SymbolGraphOf[K]) ConnectedComponentOf(key K) (component []K, err error) {
+	v, ok := sg.sym_table[key]
+	if !ok {
+		return nil, &ErrUnknownVertex{Key: key}
+	}
+
+	cc := algorithms.New(sg.sym_graph)
+	for w := int32(0); w < sg.sym_graph.V(); w++ {
+		if cc.Id(w) == cc.Id(v) {
+			component = append(component, sg.keys[w])
+		}
+	}
+
+	return
+}
+
+//
+// this function returns keys of all the vertices of the symbol
+// digraph in topological order. an error is returned if the digraph
+// has a cycle.
+//
+func (sg *SymbolDigraphOf[K]) TopologicalOrder() (order []K, err error) {
+	if cyclic, cycle := algorithms.IsDigraphAcyclic(sg.sym_graph); cyclic {
+		err = fmt.Errorf("digraph has a cycle: %v. no ordering possible", sg.live_names(cycle))
+		return
+	}
+
+	// reverse-post-order traversal is the topological sort order
+	ids := traversal.DoDFSTraversals(sg.sym_graph).ReversePost()
+
+	return sg.live_names(ids), nil
+}
+
+//
+// this function returns keys of the vertices of a directed cycle in
+// the symbol digraph, with the first and last key being the same. nil
+// is returned for acyclic digraphs.
+//
+func (sg *SymbolDigraphOf[K]) Cycle() (cycle []K) {
+	cyclic, ids := algorithms.IsDigraphAcyclic(sg.sym_graph)
+	if !cyclic {
+		return nil
+	}
+
+	return sg.live_names(ids)
+}
+
+//
+// private unexported stuff
+//
+
+func (st *symbol_table_t[K]) shortest_path(G graph.GraphOps, from, to K) (path []K, err error) {
+	ids, err := st.IndexAll([]K{from, to})
+	if err != nil {
+		return
+	}
+
+	bp, err := traversal.BidirectionalShortestPath(G, G, ids[0], ids[1])
+	if err != nil {
+		err = fmt.Errorf("no path from: '%v' to: '%v'", from, to)
+		return
+	}
+
+	return st.NameAll(bp.Path())
+}
+
+func (st *symbol_table_t[K]) neighbors(G graph.GraphOps, key K) (adj []K, err error) {
+	v, ok := st.sym_table[key]
+	if !ok {
+		return nil, &ErrUnknownVertex{Key: key}
+	}
+
+	return st.live_names(G.Adj(v)), nil
+}
+
+//
+// keys of vertices in 'ids', skipping the ones which have been
+// removed. removed vertices have no edges, so this only matters for
+// algorithms which report every vertex e.g. topological sorting.
+//
+func (st *symbol_table_t[K]) live_names(ids []int32) (keys []K) {
+	keys = make([]K, 0, len(ids))
+
+	for _, v := range ids {
+		if key, err := st.Name(v); err == nil {
+			keys = append(keys, key)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,161 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for key based algorithm
+// wrappers
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"strings"
+	"testing"
+)
+
+func TestSymbolGraphAlgorithms(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n" +
+		"ORD PHX\nATL HOU\nDEN PHX\nPHX LAX\nJFK ORD\nDEN LAS\n" +
+		"SFO SJC\n"
+
+	sg, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	path, err := sg.ShortestPath("MCO", "LAX")
+	if err != nil || len(path) != 5 || path[0] != "MCO" || path[4] != "LAX" {
+		t.Logf("unexpected shortest path: %v, error: %v\n", path, err)
+		t.Fail()
+	}
+
+	if _, err = sg.ShortestPath("MCO", "SJC"); err == nil {
+		t.Logf("found a path across components\n")
+		t.Fail()
+	}
+
+	var unknown *ErrUnknownVertex
+	if _, err = sg.ShortestPath("MCO", "BOS"); !errors.As(err, &unknown) || unknown.Key != "BOS" {
+		t.Logf("expected an unknown vertex error, got: %v\n", err)
+		t.Fail()
+	}
+
+	want := []string{"MCO", "ATL", "ORD"}
+	if got, _ := sg.Neighbors("JFK"); !slice_utils.RelaxedCmpStringSlice(&want, &got) {
+		t.Logf("neighbors: want: %v, got: %v\n", want, got)
+		t.Fail()
+	}
+
+	want = []string{"SFO", "SJC"}
+	if got, _ := sg.ConnectedComponentOf("SJC"); !slice_utils.CmpStringSlice(&want, &got) {
+		t.Logf("component: want: %v, got: %v\n", want, got)
+		t.Fail()
+	}
+
+	// removed vertices don't show up anywhere
+	sg.RemoveVertex("SFO")
+	want = []string{"SJC"}
+	if got, _ := sg.ConnectedComponentOf("SJC"); !slice_utils.CmpStringSlice(&want, &got) {
+		t.Logf("component after removal: want: %v, got: %v\n", want, got)
+		t.Fail()
+	}
+}
+
+func TestSymbolDigraphAlgorithms(t *testing.T) {
+	data := "shirt tie\ntie jacket\ntrousers shoes\nundershorts shoes\n" +
+		"undershorts trousers\ntrousers belt\nbelt jacket\nsocks shoes\nshirt belt\n"
+
+	sg, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	sg.AddVertex("watch")
+	sg.AddVertex("hat")
+	sg.RemoveVertex("hat")
+
+	if cycle := sg.Cycle(); cycle != nil {
+		t.Logf("unexpected cycle: %v\n", cycle)
+		t.Fail()
+	}
+
+	order, err := sg.TopologicalOrder()
+	if err != nil || len(order) != int(sg.G().V())-1 {
+		t.Logf("unexpected topological order: %v, error: %v\n", order, err)
+		t.FailNow()
+	}
+
+	position := make(map[string]int)
+	for i, name := range order {
+		position[name] = i
+	}
+	for v := int32(0); v < sg.G().V(); v++ {
+		for _, w := range sg.G().Adj(v) {
+			if position[sg.keys[v]] > position[sg.keys[w]] {
+				t.Logf("%s comes after %s in: %v\n", sg.keys[v], sg.keys[w], order)
+				t.Fail()
+			}
+		}
+	}
+
+	if path, err := sg.ShortestPath("undershorts", "jacket"); err != nil || len(path) != 4 {
+		t.Logf("unexpected shortest path: %v, error: %v\n", path, err)
+		t.Fail()
+	}
+
+	if _, err := sg.ShortestPath("jacket", "undershorts"); err == nil {
+		t.Logf("found a path against the edges\n")
+		t.Fail()
+	}
+
+	if path, err := sg.ShortestPath("jacket", "jacket"); err != nil || len(path) != 1 {
+		t.Logf("unexpected path to self: %v, error: %v\n", path, err)
+		t.Fail()
+	}
+
+	sg.AddEdge("jacket", "shirt")
+	if cycle := sg.Cycle(); len(cycle) != 4 || cycle[0] != cycle[len(cycle)-1] {
+		t.Logf("unexpected cycle: %v\n", cycle)
+		t.Fail()
+	}
+
+	if _, err := sg.TopologicalOrder(); err == nil || !strings.Contains(err.Error(), "jacket") {
+		t.Logf("cyclic digraph has a topological order, error: %v\n", err)
+		t.Fail()
+	}
+}
+
+func ExampleSymbolGraphOf_ShortestPath() {
+	sg := NewOf[int64]()
+	sg.AddEdge(1001, 1002)
+	sg.AddEdge(1002, 1003)
+	sg.AddEdge(1001, 1004)
+	sg.AddEdge(1004, 1003)
+	sg.AddEdge(1003, 1005)
+
+	path, _ := sg.ShortestPath(1001, 1005)
+	fmt.Println(path)
+	// Output:
+	// [1001 1002 1003 1005]
+}
@@ -2,6 +2,7 @@ package symbol_graph
 
 import (
 	"bufio"
+	"fmt"
 	"github.com/anupamk/common-utilz/line_parser"
 	"io"
 )
@@ -40,3 +41,29 @@ func load_symtab_revindex_from_reader(src *bufio.Reader, sep string) (symtab map
 
 	return
 }
+
+//
+// this function emits the stringified representation of a
+// symbol-graph i.e. its symbol table, reverse-index and the graph
+// itself, to a writer
+//
+func write_symbol_graph[K comparable](dst io.Writer, symtab map[K]int32, keys []K, g interface {
+	DumpTo(io.Writer) (int64, error)
+}) (n int64, err error) {
+	var m int
+	var gn int64
+
+	w := bufio.NewWriter(dst)
+
+	m, _ = fmt.Fprintf(w, "symbol-table: %v\nreverse-index: %v\ngraph\n", symtab, keys)
+	n += int64(m)
+
+	if err = w.Flush(); err != nil {
+		return
+	}
+
+	gn, err = g.DumpTo(dst)
+	n += gn
+
+	return
+}
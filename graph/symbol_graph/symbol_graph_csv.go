@@ -0,0 +1,281 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements loading symbol graphs from csv/tsv tables,
+// with the quoting rules of encoding/csv, so that vertex names may
+// contain the separator, quotes or even newlines
+//
+package symbol_graph
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"io"
+	"strconv"
+)
+
+//
+// options for loading symbol graphs from csv/tsv tables. columns are
+// identified either by their name in the header row (when there is
+// one), or by their 0-based index. each record is an edge from the
+// vertex named in the 'Source' column to the one named in the
+// 'Target' column, and other columns are ignored.
+//
+type CSVOptions struct {
+	Comma   rune   // field separator, ',' if zero. use '\t' for tsv
+	Comment rune   // lines starting with this are ignored, none if zero
+	Header  bool   // first record names the columns
+	Source  string // source vertex column, "0" if empty
+	Target  string // target vertex column, "1" if empty
+	Weight  string // edge weight column, only for weighted loaders
+}
+
+//
+// create a symbol-graph from a csv/tsv table in 'src'. vertices are
+// numbered in the order of their first appearance, and adjacency
+// lists follow the order of the records.
+//
+func LoadFromCSV(src io.Reader, opts CSVOptions) (sg *SymbolGraph, err error) {
+	var tbl *csv_table_t
+
+	if tbl, err = parse_csv(src, opts, false); err != nil {
+		return
+	}
+
+	sg = &SymbolGraph{}
+	sg.sym_table, sg.keys = symbol_tables_from_names(tbl.names)
+	sg.sym_graph = graph.New(int32(len(sg.keys)))
+
+	for _, e := range tbl.edges {
+		sg.sym_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+// digraph version of LoadFromCSV(...)
+func DigraphFromCSV(src io.Reader, opts CSVOptions) (sg *SymbolDigraph, err error) {
+	var tbl *csv_table_t
+
+	if tbl, err = parse_csv(src, opts, false); err != nil {
+		return
+	}
+
+	sg = &SymbolDigraph{}
+	sg.sym_table, sg.keys = symbol_tables_from_names(tbl.names)
+	sg.sym_graph = graph.CreateDigraph(int32(len(sg.keys)))
+
+	for _, e := range tbl.edges {
+		sg.sym_graph.AddEdge(e[0], e[1])
+	}
+
+	return
+}
+
+//
+// same as LoadFromCSV(...), with edge weights taken from the
+// (mandatory) 'Weight' column. the weighted graph shares vertex ids,
+// and the order of adjacency lists with the symbol-graph.
+//
+func WeightedFromCSV(src io.Reader, opts CSVOptions) (sg *SymbolGraph, wg *graph.WeightedGraph, err error) {
+	var tbl *csv_table_t
+
+	if tbl, err = parse_csv(src, opts, true); err != nil {
+		return
+	}
+
+	sg = &SymbolGraph{}
+	sg.sym_table, sg.keys = symbol_tables_from_names(tbl.names)
+	sg.sym_graph = graph.New(int32(len(sg.keys)))
+	wg = graph.NewWeighted(int32(len(sg.keys)))
+
+	for i, e := range tbl.edges {
+		sg.sym_graph.AddEdge(e[0], e[1])
+		wg.AddEdge(e[0], e[1], tbl.weights[i])
+	}
+
+	return
+}
+
+// digraph version of WeightedFromCSV(...)
+func WeightedDigraphFromCSV(src io.Reader, opts CSVOptions) (sg *SymbolDigraph, wg *graph.WeightedDigraph, err error) {
+	var tbl *csv_table_t
+
+	if tbl, err = parse_csv(src, opts, true); err != nil {
+		return
+	}
+
+	sg = &SymbolDigraph{}
+	sg.sym_table, sg.keys = symbol_tables_from_names(tbl.names)
+	sg.sym_graph = graph.CreateDigraph(int32(len(sg.keys)))
+	wg = graph.CreateWeightedDigraph(int32(len(sg.keys)))
+
+	for i, e := range tbl.edges {
+		sg.sym_graph.AddEdge(e[0], e[1])
+		wg.AddEdge(e[0], e[1], tbl.weights[i])
+	}
+
+	return
+}
+
+//
+// private unexported stuff
+//
+
+// contents of a csv table, reduced to what is needed for the graph
+type csv_table_t struct {
+	names   []string   // vertex names in order of first appearance
+	edges   [][2]int32 // indices into 'names'
+	weights []float64  // edge weights, if asked for
+}
+
+//
+// read all records from 'src', resolving the columns named in 'opts'.
+// errors carry the line number of the offending record.
+//
+func parse_csv(src io.Reader, opts CSVOptions, weighted bool) (tbl *csv_table_t, err error) {
+	var record []string
+	var src_col, dst_col, wt_col int
+
+	r := csv.NewReader(src)
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+	if opts.Comma != 0 {
+		r.Comma = opts.Comma
+	}
+	r.Comment = opts.Comment
+
+	// header
+	var header []string
+	if opts.Header {
+		if record, err = r.Read(); err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("csv: missing header row")
+			}
+			return nil, err
+		}
+		header = append(header, record...)
+	}
+
+	if src_col, err = csv_column(header, opts.Source, "0"); err != nil {
+		return
+	}
+	if dst_col, err = csv_column(header, opts.Target, "1"); err != nil {
+		return
+	}
+
+	min_fields := max_int(src_col, dst_col) + 1
+	if weighted {
+		if opts.Weight == "" {
+			return nil, fmt.Errorf("csv: no weight column given")
+		}
+		if wt_col, err = csv_column(header, opts.Weight, ""); err != nil {
+			return
+		}
+		min_fields = max_int(min_fields, wt_col+1)
+	}
+
+	// records
+	tbl = &csv_table_t{}
+	symtab := make(map[string]int32)
+
+	vertex := func(name string) int32 {
+		v, ok := symtab[name]
+		if !ok {
+			v = int32(len(tbl.names))
+			symtab[name] = v
+			tbl.names = append(tbl.names, name)
+		}
+		return v
+	}
+
+	for {
+		if record, err = r.Read(); err != nil {
+			if err == io.EOF {
+				err = nil
+				break
+			}
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if len(record) < min_fields {
+			return nil, fmt.Errorf("csv: line %d: expected at least %d fields, found %d", line, min_fields, len(record))
+		}
+
+		if record[src_col] == "" || record[dst_col] == "" {
+			return nil, fmt.Errorf("csv: line %d: empty vertex name", line)
+		}
+
+		if weighted {
+			var weight float64
+
+			if weight, err = strconv.ParseFloat(record[wt_col], 64); err != nil {
+				return nil, fmt.Errorf("csv: line %d: malformed weight %q", line, record[wt_col])
+			}
+			tbl.weights = append(tbl.weights, weight)
+		}
+
+		tbl.edges = append(tbl.edges, [2]int32{vertex(record[src_col]), vertex(record[dst_col])})
+	}
+
+	return
+}
+
+//
+// resolve a column by its name in the header row, or else by its
+// 0-based index. an empty 'column' stands for 'fallback'
+//
+func csv_column(header []string, column, fallback string) (int, error) {
+	if column == "" {
+		column = fallback
+	}
+
+	for i, name := range header {
+		if name == column {
+			return i, nil
+		}
+	}
+
+	if i, err := strconv.Atoi(column); err == nil && i >= 0 {
+		return i, nil
+	}
+
+	return 0, fmt.Errorf("csv: unknown column %q", column)
+}
+
+func max_int(x, y int) int {
+	if x > y {
+		return x
+	}
+
+	return y
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for csv/tsv loading
+//
+package symbol_graph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleLoadFromCSV() {
+	data := "movie,year,performer\n" +
+		"\"Godfather, The\",1972,\"Pacino, Al\"\n" +
+		"\"Godfather, The\",1972,\"Brando, Marlon\"\n" +
+		"Heat,1995,\"Pacino, Al\"\n"
+
+	sg, _ := LoadFromCSV(strings.NewReader(data), CSVOptions{
+		Header: true,
+		Source: "performer",
+		Target: "movie",
+	})
+
+	for v := int32(0); v < sg.G().V(); v++ {
+		name, _ := sg.Name(v)
+		fmt.Printf("%s:", name)
+		for _, w := range sg.G().Adj(v) {
+			wname, _ := sg.Name(w)
+			fmt.Printf(" [%s]", wname)
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	// Pacino, Al: [Godfather, The] [Heat]
+	// Godfather, The: [Pacino, Al] [Brando, Marlon]
+	// Brando, Marlon: [Godfather, The]
+	// Heat: [Pacino, Al]
+}
+
+func TestWeightedFromCSV(t *testing.T) {
+	data := "# from\tto\tkm\n" +
+		"a/b\tc\t2.5\n" +
+		"c\t\"d\te\"\t1\n" +
+		"a/b\t\"d\te\"\t7\n"
+
+	opts := CSVOptions{Comma: '\t', Comment: '#', Weight: "2"}
+
+	sg, wg, err := WeightedDigraphFromCSV(strings.NewReader(data), opts)
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if sg.G().V() != 3 || !sg.Contains("a/b") || !sg.Contains("d\te") {
+		t.Logf("unexpected symbol digraph:\n%s\n", sg)
+		t.FailNow()
+	}
+
+	v := sg.Index("a/b")
+	adj, weights := wg.Adj(v), wg.Weights(v)
+	if fmt.Sprint(adj, weights) != "[1 2] [2.5 7]" || fmt.Sprint(sg.G().Adj(v)) != "[1 2]" {
+		t.Logf("unexpected adjacency: %v, weights: %v\n", adj, weights)
+		t.Fail()
+	}
+
+	g, ug, err := WeightedFromCSV(strings.NewReader(data), opts)
+	if err != nil || g.G().E() != 3 || ug.E() != 3 {
+		t.Logf("unexpected graph: %v, error: %v\n", g, err)
+		t.Fail()
+	}
+}
+
+func TestCSVErrors(t *testing.T) {
+	test_cases := []struct {
+		data     string
+		opts     CSVOptions
+		weighted bool
+		reason   string
+	}{
+		{"", CSVOptions{Header: true}, false, "missing header row"},
+		{"x,y\n", CSVOptions{Header: true, Source: "from"}, false, "unknown column \"from\""},
+		{"a,b\nc\n", CSVOptions{}, false, "line 2: expected at least 2 fields"},
+		{"a,b\n,c\n", CSVOptions{}, false, "line 2: empty vertex name"},
+		{"a,\"b\n", CSVOptions{}, false, "extraneous or missing \" in quoted-field"},
+		{"a,b\n", CSVOptions{}, true, "no weight column"},
+		{"a,b,1\nb,c,one\n", CSVOptions{Weight: "2"}, true, "line 2: malformed weight \"one\""},
+	}
+
+	for i, tc := range test_cases {
+		var err error
+
+		if tc.weighted {
+			_, _, err = WeightedFromCSV(strings.NewReader(tc.data), tc.opts)
+		} else {
+			_, err = LoadFromCSV(strings.NewReader(tc.data), tc.opts)
+		}
+
+		if err == nil || !strings.Contains(err.Error(), tc.reason) {
+			t.Logf("test-case: %d, expected: %s, got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+}
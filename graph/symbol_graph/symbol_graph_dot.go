@@ -0,0 +1,200 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements reading and writing symbol graphs in
+// graphviz's dot language, vertex names are used as node ids
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"io"
+)
+
+//
+// this function emits the symbol-graph in dot language, with vertex
+// names as node ids. any VertexName in 'opts' is overridden.
+//
+func (sg *SymbolGraph) WriteDot(w io.Writer, opts *graph.DotOptions) error {
+	g, keys, vmap := sg.live_graph()
+	return g.WriteDot(w, with_vertex_names(opts, keys, vmap))
+}
+
+//
+// this function emits the symbol-digraph in dot language, with
+// vertex names as node ids. any VertexName in 'opts' is overridden.
+//
+func (sg *SymbolDigraph) WriteDot(w io.Writer, opts *graph.DotOptions) error {
+	g, keys, vmap := sg.live_graph()
+	return g.WriteDot(w, with_vertex_names(opts, keys, vmap))
+}
+
+//
+// this function is called to create a symbol-graph from its
+// definition in dot language. vertex ids are assigned in order of
+// first appearance. an error is signalled if the input is a digraph.
+// the graph of a 'strict' definition rejects parallel edges, and
+// repeated edges in the definition are dropped.
+//
+func LoadFromDot(src *bufio.Reader) (sg *SymbolGraph, err error) {
+	var dg *dot_graph_t
+
+	if dg, err = parse_dot(src); err != nil {
+		return
+	}
+
+	if dg.directed {
+		err = fmt.Errorf("dot: expected a graph, found a digraph")
+		return
+	}
+
+	sg = &SymbolGraph{}
+	sg.sym_table, sg.keys = symbol_tables_from_names(dg.names)
+	sg.sym_graph = graph.New(int32(len(sg.keys)))
+	if dg.strict {
+		sg.sym_graph.SetEdgePolicy(graph.RejectParallelEdges)
+	}
+
+	for _, e := range dg.edges {
+		sg.sym_graph.AddEdge(sg.sym_table[e[0]], sg.sym_table[e[1]])
+	}
+
+	return
+}
+
+//
+// this function is called to create a symbol-digraph from its
+// definition in dot language. vertex ids are assigned in order of
+// first appearance. an error is signalled if the input is an
+// undirected graph. the digraph of a 'strict' definition rejects
+// parallel edges, and repeated edges in the definition are dropped.
+//
+func DigraphFromDot(src *bufio.Reader) (sg *SymbolDigraph, err error) {
+	var dg *dot_graph_t
+
+	if dg, err = parse_dot(src); err != nil {
+		return
+	}
+
+	if !dg.directed {
+		err = fmt.Errorf("dot: expected a digraph, found a graph")
+		return
+	}
+
+	sg = &SymbolDigraph{}
+	sg.sym_table, sg.keys = symbol_tables_from_names(dg.names)
+	sg.sym_graph = graph.CreateDigraph(int32(len(sg.keys)))
+	if dg.strict {
+		sg.sym_graph.SetEdgePolicy(graph.RejectParallelEdges)
+	}
+
+	for _, e := range dg.edges {
+		sg.sym_graph.AddEdge(sg.sym_table[e[0]], sg.sym_table[e[1]])
+	}
+
+	return
+}
+
+//
+// these are convenience interfaces over LoadFromDot(...) and
+// DigraphFromDot(...) to create symbol graphs from their dot
+// definition stored in a file identified by 'fname'
+//
+func LoadFromDotFile(fname string) (sg *SymbolGraph, err error) {
+	var f io.ReadCloser
+
+	if f, err = graph.OpenFile(fname); err != nil {
+		return
+	}
+	defer f.Close()
+
+	return LoadFromDot(bufio.NewReader(f))
+}
+
+func DigraphFromDotFile(fname string) (sg *SymbolDigraph, err error) {
+	var f io.ReadCloser
+
+	if f, err = graph.OpenFile(fname); err != nil {
+		return
+	}
+	defer f.Close()
+
+	return DigraphFromDot(bufio.NewReader(f))
+}
+
+//
+// private unexported stuff
+//
+
+//
+// copy of dot options, naming vertices as per 'keys'. when removed
+// vertices have been compacted out as per 'vmap', vertex ids in the
+// options are translated, and removed vertices dropped from the
+// highlighted path.
+//
+func with_vertex_names(opts *graph.DotOptions, keys []string, vmap *graph.SubgraphMap) *graph.DotOptions {
+	named := graph.DotOptions{}
+	if opts != nil {
+		named = *opts
+	}
+
+	named.VertexName = func(v int32) string { return keys[v] }
+	if vmap == nil {
+		return &named
+	}
+
+	highlight := named.Highlight
+	named.Highlight = nil
+	for _, old := range highlight {
+		if v, ok := vmap.New(old); ok {
+			named.Highlight = append(named.Highlight, v)
+		}
+	}
+
+	if component := named.Component; component != nil {
+		named.Component = func(v int32) int32 { return component(vmap.Old(v)) }
+	}
+
+	return &named
+}
+
+// symbol table, and reverse index for a list of distinct names
+func symbol_tables_from_names[K comparable](names []K) (symtab map[K]int32, keys []K) {
+	symtab = make(map[K]int32, len(names))
+	keys = make([]K, len(names))
+
+	for i, name := range names {
+		symtab[name] = int32(i)
+		keys[i] = name
+	}
+
+	return
+}
@@ -0,0 +1,243 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for dot reading/writing
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"strings"
+	"testing"
+)
+
+func ExampleDigraphFromDot() {
+	dot := `
+# a tiny course schedule
+strict digraph "courses" {
+    rankdir = LR;
+    node [shape=box];
+
+    Calculus -> "Linear Algebra" -> "Machine Learning" [color=blue];
+    /* isolated, but still a vertex */
+    Robotics;
+    "Intro to CS" -> Algorithms; // trailing comment
+    "Intro to CS" -> "Machine Learning"
+}
+`
+	sg, err := DigraphFromDot(bufio.NewReader(strings.NewReader(dot)))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for g, v := sg.G(), int32(0); v < g.V(); v++ {
+		name, _ := sg.Name(v)
+		fmt.Printf("%s:", name)
+		for _, w := range g.Adj(v) {
+			wname, _ := sg.Name(w)
+			fmt.Printf(" [%s]", wname)
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	// Calculus: [Linear Algebra]
+	// Linear Algebra: [Machine Learning]
+	// Machine Learning:
+	// Robotics:
+	// Intro to CS: [Algorithms] [Machine Learning]
+	// Algorithms:
+}
+
+//
+// writing a symbol-graph in dot, and reading it back must result in
+// the same named adjacency for every vertex
+//
+func TestDotRoundTrip(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n" +
+		"ORD PHX\nATL HOU\nDEN PHX\nPHX LAX\nJFK ORD\nDEN LAS\n" +
+		"DFW HOU\nORD ATL\nLAS LAX\nATL MCO\nHOU MCO\nLAS PHX\n"
+
+	sg1, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	var buf bytes.Buffer
+	if err := sg1.WriteDot(&buf, nil); err != nil {
+		t.Logf("unexpected error while writing: %s\n", err)
+		t.FailNow()
+	}
+
+	sg2, err := LoadFromDot(bufio.NewReader(&buf))
+	if err != nil {
+		t.Logf("unexpected error while reading: %s\n", err)
+		t.FailNow()
+	}
+
+	named_adj := func(sg *SymbolGraph, name string) (adj []string) {
+		for _, w := range sg.G().Adj(sg.Index(name)) {
+			wname, _ := sg.Name(w)
+			adj = append(adj, wname)
+		}
+		return
+	}
+
+	if sg1.G().V() != sg2.G().V() || sg1.G().E() != sg2.G().E() {
+		t.Logf("vertex/edge count mismatch, want: %d/%d, got: %d/%d\n",
+			sg1.G().V(), sg1.G().E(), sg2.G().V(), sg2.G().E())
+		t.FailNow()
+	}
+
+	for v := int32(0); v < sg1.G().V(); v++ {
+		name, _ := sg1.Name(v)
+		want, got := named_adj(sg1, name), named_adj(sg2, name)
+
+		if !slice_utils.RelaxedCmpStringSlice(&want, &got) {
+			t.Logf("vertex: %s, want-adj: %v, got-adj: %v\n", name, want, got)
+			t.Fail()
+		}
+	}
+}
+
+//
+// names with quotes, and backslashes (trailing ones in particular)
+// must survive a trip through the dot writer
+//
+func TestDotRoundTripEscapes(t *testing.T) {
+	names := []string{`C:\tmp\`, `say "hi"`, `\`, `"`, `a\"b`, "plain"}
+
+	sg1 := NewDigraph()
+	for i := 1; i < len(names); i++ {
+		sg1.AddEdge(names[i-1], names[i])
+	}
+
+	var buf bytes.Buffer
+	if err := sg1.WriteDot(&buf, nil); err != nil {
+		t.Logf("unexpected error while writing: %s\n", err)
+		t.FailNow()
+	}
+
+	sg2, err := DigraphFromDot(bufio.NewReader(strings.NewReader(buf.String())))
+	if err != nil {
+		t.Logf("unexpected error while reading: %s\n%s\n", err, buf.String())
+		t.FailNow()
+	}
+
+	for i, name := range names {
+		v, ok := sg2.Lookup(name)
+		if !ok {
+			t.Logf("name: %q didn't survive the round trip\n%s\n", name, buf.String())
+			t.Fail()
+			continue
+		}
+
+		if i > 0 && !sg2.G().HasEdge(sg2.Index(names[i-1]), v) {
+			t.Logf("edge: %q -> %q didn't survive the round trip\n", names[i-1], name)
+			t.Fail()
+		}
+	}
+}
+
+//
+// strict graphs have at most one edge between a pair of vertices,
+// which is something other graphs don't care about
+//
+func TestDotStrict(t *testing.T) {
+	test_cases := []struct {
+		dot    string
+		want_e int32
+	}{
+		{"strict graph { a -- b; b -- a; a -- b -- c; a -- a; a -- a }", 3},
+		{"graph { a -- b; b -- a; a -- b -- c }", 4},
+		{"strict digraph { a -> b; b -> a; a -> b -> c; c -> c; c -> c }", 4},
+		{"digraph { a -> b; a -> b }", 2},
+	}
+
+	for _, tc := range test_cases {
+		var g interface{ E() int32 }
+		var err error
+
+		src := bufio.NewReader(strings.NewReader(tc.dot))
+		if strings.Contains(tc.dot, "digraph") {
+			var sg *SymbolDigraph
+			if sg, err = DigraphFromDot(src); err == nil {
+				g = sg.G()
+			}
+		} else {
+			var sg *SymbolGraph
+			if sg, err = LoadFromDot(src); err == nil {
+				g = sg.G()
+			}
+		}
+
+		if err != nil {
+			t.Logf("input: %q, unexpected error: %s\n", tc.dot, err)
+			t.Fail()
+			continue
+		}
+
+		if g.E() != tc.want_e {
+			t.Logf("input: %q, want-edges: %d, got-edges: %d\n", tc.dot, tc.want_e, g.E())
+			t.Fail()
+		}
+	}
+}
+
+func TestDotParseErrors(t *testing.T) {
+	bad_input := []struct {
+		dot  string
+		line string
+	}{
+		{"digraph { a -- b }", "line 1"},
+		{"graph {\n a -> b\n}", "line 2"},
+		{"graph {\n a -- b\n", "line 3"},
+		{"graph { subgraph s { a } }", "line 1"},
+		{"graph {\n\n a:n -- b }", "line 3"},
+		{"graph { a [color=red }", "line 1"},
+		{"graph { \"a -- b }", "line 1"},
+		{"tree { a }", "line 1"},
+		{"graph { a } b", "line 1"},
+	}
+
+	for _, bad := range bad_input {
+		_, err := LoadFromDot(bufio.NewReader(strings.NewReader(bad.dot)))
+		if err == nil || !strings.Contains(err.Error(), bad.line) {
+			t.Logf("input: %q, expected error at %s, got: %v\n", bad.dot, bad.line, err)
+			t.Fail()
+		}
+	}
+
+	// right syntax, wrong kind of graph
+	if _, err := DigraphFromDot(bufio.NewReader(strings.NewReader("graph { a -- b }"))); err == nil {
+		t.Logf("expected error loading a graph as digraph\n")
+		t.Fail()
+	}
+}
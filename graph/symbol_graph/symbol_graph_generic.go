@@ -0,0 +1,222 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements symbol graphs, and symbol digraphs whose
+// vertices are keyed by values of any comparable type e.g. uuids,
+// int64 ids, or structs. SymbolGraph and SymbolDigraph are the string
+// keyed instantiations of these.
+//
+package symbol_graph
+
+import (
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/traversal"
+	"io"
+	"strings"
+)
+
+//
+// a symbol-graph with vertices keyed by values of type 'K'
+//
+type SymbolGraphOf[K comparable] struct {
+	symbol_table_t[K]
+	sym_graph *graph.Graph // resultant graph
+}
+
+//
+// a symbol-digraph with vertices keyed by values of type 'K'
+//
+type SymbolDigraphOf[K comparable] struct {
+	symbol_table_t[K]
+	sym_graph *graph.Digraph // resultant graph
+}
+
+//
+// create a new, empty symbol-graph keyed by values of type 'K', to be
+// populated with AddVertex(...) and AddEdge(...)
+//
+func NewOf[K comparable]() *SymbolGraphOf[K] {
+	return &SymbolGraphOf[K]{
+		symbol_table_t: new_symbol_table[K](),
+		sym_graph:      graph.New(0),
+	}
+}
+
+//
+// create a new, empty symbol-digraph keyed by values of type 'K', to
+// be populated with AddVertex(...) and AddEdge(...)
+//
+func NewDigraphOf[K comparable]() *SymbolDigraphOf[K] {
+	return &SymbolDigraphOf[K]{
+		symbol_table_t: new_symbol_table[K](),
+		sym_graph:      graph.CreateDigraph(0),
+	}
+}
+
+//
+// this function returns a pointer to the underlying graph in the
+// symbol graph.
+func (sg *SymbolGraphOf[K]) G() (g *graph.Graph) { return sg.sym_graph }
+
+//
+// this function returns a pointer to the underlying digraph in the
+// symbol digraph.
+func (sg *SymbolDigraphOf[K]) G() (g *graph.Digraph) { return sg.sym_graph }
+
+//
+// this function returns the stringified representation of the
+// symbol-graph
+//
+func (sg *SymbolGraphOf[K]) String() string {
+	var sb strings.Builder
+
+	sg.WriteTo(&sb)
+	return sb.String()
+}
+
+func (sg *SymbolDigraphOf[K]) String() string {
+	var sb strings.Builder
+
+	sg.WriteTo(&sb)
+	return sb.String()
+}
+
+//
+// streaming version of String(...), which implements io.WriterTo
+//
+func (sg *SymbolGraphOf[K]) WriteTo(w io.Writer) (n int64, err error) {
+	return write_symbol_graph(w, sg.sym_table, sg.keys, sg.sym_graph)
+}
+
+func (sg *SymbolDigraphOf[K]) WriteTo(w io.Writer) (n int64, err error) {
+	return write_symbol_graph(w, sg.sym_table, sg.keys, sg.sym_graph)
+}
+
+//
+// keys of all vertices reachable from 'from', in breadth-first order
+//
+func (sg *SymbolGraphOf[K]) BFSOrder(from K) (order []K, err error) {
+	return sg.walk(sg.sym_graph, from, traversal.BFSGraphSubsetWalker)
+}
+
+func (sg *SymbolDigraphOf[K]) BFSOrder(from K) (order []K, err error) {
+	return sg.walk(sg.sym_graph, from, traversal.BFSGraphSubsetWalker)
+}
+
+//
+// keys of all vertices reachable from 'from', in the order in which
+// traversal.DFSGraphSubsetWalker(...) visits them
+//
+func (sg *SymbolGraphOf[K]) DFSOrder(from K) (order []K, err error) {
+	return sg.walk(sg.sym_graph, from, traversal.DFSGraphSubsetWalker)
+}
+
+func (sg *SymbolDigraphOf[K]) DFSOrder(from K) (order []K, err error) {
+	return sg.walk(sg.sym_graph, from, traversal.DFSGraphSubsetWalker)
+}
+
+//
+// private unexported stuff
+//
+
+//
+// mapping between keys and vertex ids, shared by symbol graphs and
+// symbol digraphs
+//
+type symbol_table_t[K comparable] struct {
+	sym_table map[K]int32 // vertex-name -> vertex-index
+	keys      []K         // vertex-index -> vertex-name
+}
+
+func new_symbol_table[K comparable]() symbol_table_t[K] {
+	return symbol_table_t[K]{
+		sym_table: make(map[K]int32),
+		keys:      make([]K, 0),
+	}
+}
+
+//
+// this function returns true if the symbol-graph contains 'key' as a
+// vertex
+//
+func (st *symbol_table_t[K]) Contains(key K) bool {
+	_, ok := st.sym_table[key]
+	return ok
+}
+
+//
+// this function returns the index associated with a given key, if the
+// key doesn't exist, it panics (with an *ErrUnknownVertex). thus
+// clients, are expected to ensure that the key is available before
+// coming here, or use Lookup(...) instead.
+//
+func (st *symbol_table_t[K]) Index(key K) (idx int32) {
+	var ok bool
+
+	if idx, ok = st.sym_table[key]; !ok {
+		panic(&ErrUnknownVertex{Key: key})
+	}
+
+	return
+}
+
+//
+// this function returns the name associated with a given vertex. if
+// the vertex is invalid, or has been removed, an *ErrUnknownVertex is
+// returned
+//
+func (st *symbol_table_t[K]) Name(vertex_id int32) (name K, err error) {
+	if vertex_id < 0 || vertex_id >= int32(len(st.keys)) {
+		err = &ErrUnknownVertex{Id: vertex_id}
+		return
+	}
+
+	name = st.keys[vertex_id]
+	if id, ok := st.sym_table[name]; !ok || id != vertex_id {
+		var none K
+		name, err = none, &ErrUnknownVertex{Id: vertex_id, Removed: true}
+	}
+
+	return
+}
+
+// walk the subset of 'G' reachable from 'from', collecting keys
+func (st *symbol_table_t[K]) walk(G graph.GraphOps, from K, walker func(graph.GraphOps, int32) traversal.GraphSubsetWalker) (order []K, err error) {
+	source, ok := st.sym_table[from]
+	if !ok {
+		return nil, &ErrUnknownVertex{Key: from}
+	}
+
+	next := walker(G, source)
+	for edge, werr := next(); werr == nil; edge, werr = next() {
+		order = append(order, st.keys[edge.Dst])
+	}
+
+	return
+}
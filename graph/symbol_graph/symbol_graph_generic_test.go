@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for generic symbol graphs
+//
+package symbol_graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleSymbolGraphOf() {
+	type city struct {
+		name    string
+		country string
+	}
+
+	sg := NewOf[city]()
+	paris, lyon, turin := city{"paris", "fr"}, city{"lyon", "fr"}, city{"turin", "it"}
+
+	sg.AddEdge(paris, lyon)
+	sg.AddEdge(lyon, turin)
+
+	order, _ := sg.BFSOrder(paris)
+	fmt.Println(order, sg.Index(turin))
+
+	// Output:
+	// [{paris fr} {lyon fr} {turin it}] 2
+}
+
+func TestSymbolDigraphOf(t *testing.T) {
+	sg := NewDigraphOf[int64]()
+
+	for _, e := range [][2]int64{{1 << 40, 7}, {7, 1 << 40}, {7, -3}, {-3, 99}} {
+		sg.AddEdge(e[0], e[1])
+	}
+
+	if !sg.Contains(-3) || sg.Contains(3) || sg.G().V() != 4 || sg.G().E() != 4 {
+		t.Logf("unexpected symbol digraph:\n%s\n", sg)
+		t.FailNow()
+	}
+
+	if name, err := sg.Name(sg.Index(99)); err != nil || name != 99 {
+		t.Logf("unexpected name: %d, error: %v\n", name, err)
+		t.Fail()
+	}
+
+	bfs, _ := sg.BFSOrder(7)
+	if fmt.Sprint(bfs) != "[7 1099511627776 -3 99]" {
+		t.Logf("unexpected bfs order: %v\n", bfs)
+		t.Fail()
+	}
+
+	dfs, _ := sg.DFSOrder(-3)
+	if fmt.Sprint(dfs) != "[99 -3]" {
+		t.Logf("unexpected dfs order: %v\n", dfs)
+		t.Fail()
+	}
+
+	if _, err := sg.BFSOrder(42); err == nil {
+		t.Logf("expected an error for an unknown key\n")
+		t.Fail()
+	}
+}
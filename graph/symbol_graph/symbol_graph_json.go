@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements reading and writing symbol graphs in the json
+// graph format (jgf), vertex names are carried as node labels
+//
+package symbol_graph
+
+import (
+	"bytes"
+	"github.com/anupamk/common-utilz/graph"
+	"io"
+)
+
+//
+// these functions emit the symbol-graph (or symbol-digraph) as a jgf
+// document, with vertex names as node labels. removed vertices are
+// left out, and the remaining ones renumbered.
+//
+func (sg *SymbolGraph) WriteJSON(w io.Writer) error {
+	g, keys, _ := sg.live_graph()
+	return g.WriteJSON(w, keys)
+}
+
+func (sg *SymbolDigraph) WriteJSON(w io.Writer) error {
+	g, keys, _ := sg.live_graph()
+	return g.WriteJSON(w, keys)
+}
+
+//
+// these functions are called to create a symbol-graph (or
+// symbol-digraph) from its jgf definition. every node must carry a
+// distinct label, which becomes the vertex name.
+//
+func LoadFromJSON(src io.Reader) (sg *SymbolGraph, err error) {
+	g, labels, err := graph.LoadFromJSON(src)
+	if err != nil {
+		return
+	}
+
+	return symbol_graph_from_labels(g, labels)
+}
+
+func DigraphFromJSON(src io.Reader) (sg *SymbolDigraph, err error) {
+	g, labels, err := graph.LoadDigraphFromJSON(src)
+	if err != nil {
+		return
+	}
+
+	return symbol_digraph_from_labels(g, labels)
+}
+
+//
+// json.Marshaler and json.Unmarshaler implementations, these are thin
+// wrappers over WriteJSON(...) and XXXFromJSON(...)
+//
+func (sg *SymbolGraph) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := sg.WriteJSON(&buf)
+	return buf.Bytes(), err
+}
+
+func (sg *SymbolGraph) UnmarshalJSON(data []byte) error {
+	loaded, err := LoadFromJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	*sg = *loaded
+	return nil
+}
+
+func (sg *SymbolDigraph) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := sg.WriteJSON(&buf)
+	return buf.Bytes(), err
+}
+
+func (sg *SymbolDigraph) UnmarshalJSON(data []byte) error {
+	loaded, err := DigraphFromJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	*sg = *loaded
+	return nil
+}
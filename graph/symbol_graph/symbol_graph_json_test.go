@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for jgf reading/writing
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"strings"
+	"testing"
+)
+
+//
+// symbol-graphs and symbol-digraphs must survive a trip through
+// encoding/json with their names and adjacencies intact
+//
+func TestJSONRoundTrip(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n" +
+		"ORD PHX\nATL HOU\nDEN PHX\nPHX LAX\nJFK ORD\nDEN LAS\n"
+
+	g1, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	dg1, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	doc, err := json.Marshal(map[string]interface{}{"routes": g1, "flights": dg1})
+	if err != nil {
+		t.Logf("unexpected error while marshaling: %s\n", err)
+		t.FailNow()
+	}
+
+	var decoded struct {
+		Routes  SymbolGraph   `json:"routes"`
+		Flights SymbolDigraph `json:"flights"`
+	}
+	if err = json.Unmarshal(doc, &decoded); err != nil {
+		t.Logf("unexpected error while unmarshaling: %s\n", err)
+		t.FailNow()
+	}
+
+	g2, dg2 := &decoded.Routes, &decoded.Flights
+	if g1.G().E() != g2.G().E() || dg1.G().E() != dg2.G().E() {
+		t.Logf("edge count mismatch\n")
+		t.FailNow()
+	}
+
+	for v := int32(0); v < g1.G().V(); v++ {
+		name, _ := g1.Name(v)
+
+		var want, got []string
+		for _, w := range g1.G().Adj(v) {
+			wname, _ := g1.Name(w)
+			want = append(want, wname)
+		}
+		for _, w := range g2.G().Adj(g2.Index(name)) {
+			wname, _ := g2.Name(w)
+			got = append(got, wname)
+		}
+
+		if !slice_utils.RelaxedCmpStringSlice(&want, &got) {
+			t.Logf("graph: vertex: %s, want-adj: %v, got-adj: %v\n", name, want, got)
+			t.Fail()
+		}
+
+		want, got = digraph_named_adj(dg1, name), digraph_named_adj(dg2, name)
+		if !slice_utils.CmpStringSlice(&want, &got) {
+			t.Logf("digraph: vertex: %s, want-adj: %v, got-adj: %v\n", name, want, got)
+			t.Fail()
+		}
+	}
+}
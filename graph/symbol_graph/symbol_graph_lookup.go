@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements non-panicking, and bulk lookups between keys
+// and vertex ids
+//
+package symbol_graph
+
+import (
+	"fmt"
+)
+
+//
+// error returned for lookups of vertices which aren't in the symbol
+// graph, either by their id, or by their key
+//
+type ErrUnknownVertex struct {
+	Id      int32       // offending vertex id, for lookups by id
+	Key     interface{} // offending key, for lookups by key (nil otherwise)
+	Removed bool        // the vertex id was valid, but has been removed
+}
+
+func (e *ErrUnknownVertex) Error() string {
+	switch {
+	case e.Key != nil:
+		return fmt.Sprintf("key: '%v' doesn't exist", e.Key)
+	case e.Removed:
+		return fmt.Sprintf("vertex: '%d' has been removed", e.Id)
+	}
+
+	return fmt.Sprintf("vertex: '%d' doesn't exist", e.Id)
+}
+
+//
+// this function returns the index associated with a given key, and
+// whether the key exists at all
+//
+func (st *symbol_table_t[K]) Lookup(key K) (idx int32, ok bool) {
+	idx, ok = st.sym_table[key]
+	return
+}
+
+//
+// convert a list of keys to their vertex ids e.g. to feed them to
+// graph algorithms. the first unknown key results in an
+// *ErrUnknownVertex
+//
+func (st *symbol_table_t[K]) IndexAll(keys []K) (ids []int32, err error) {
+	ids = make([]int32, len(keys))
+
+	for i, key := range keys {
+		var ok bool
+
+		if ids[i], ok = st.sym_table[key]; !ok {
+			return nil, &ErrUnknownVertex{Key: key}
+		}
+	}
+
+	return
+}
+
+//
+// convert a list of vertex ids e.g. a path returned by some graph
+// algorithm, to their keys. the first unknown (or removed) vertex
+// results in an *ErrUnknownVertex
+//
+func (st *symbol_table_t[K]) NameAll(ids []int32) (keys []K, err error) {
+	keys = make([]K, len(ids))
+
+	for i, v := range ids {
+		if keys[i], err = st.Name(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
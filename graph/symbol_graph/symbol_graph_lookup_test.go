@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for symbol graph lookups
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n"
+	sg, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	if v, ok := sg.Lookup("ORD"); !ok || v != 2 {
+		t.Logf("ORD: expected: 2, got: %d, %v\n", v, ok)
+		t.Fail()
+	}
+
+	if _, ok := sg.Lookup("LAX"); ok {
+		t.Logf("LAX: unexpectedly found\n")
+		t.Fail()
+	}
+
+	// out of range, in either direction, are errors and not panics
+	V := sg.G().V()
+	for _, v := range []int32{-1, V, V + 1} {
+		_, err := sg.Name(v)
+		if e, ok := err.(*ErrUnknownVertex); !ok || e.Id != v || e.Removed {
+			t.Logf("vertex: %d, unexpected error: %v\n", v, err)
+			t.Fail()
+		}
+	}
+
+	sg.RemoveVertex("HOU")
+	if _, err := sg.Name(4); err == nil || !err.(*ErrUnknownVertex).Removed {
+		t.Logf("removed vertex: unexpected error: %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestIndexAllNameAll(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n"
+	sg, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	names := []string{"PHX", "JFK", "DFW"}
+	ids, err := sg.IndexAll(names)
+	if err != nil || len(ids) != 3 || ids[1] != 0 {
+		t.Logf("unexpected ids: %v, error: %v\n", ids, err)
+		t.FailNow()
+	}
+
+	back, err := sg.NameAll(ids)
+	if err != nil || !slice_utils.CmpStringSlice(&names, &back) {
+		t.Logf("expected: %v, got: %v, error: %v\n", names, back, err)
+		t.Fail()
+	}
+
+	if _, err = sg.IndexAll([]string{"JFK", "LAX"}); err == nil || err.(*ErrUnknownVertex).Key != "LAX" {
+		t.Logf("unexpected error: %v\n", err)
+		t.Fail()
+	}
+
+	if _, err = sg.NameAll([]int32{0, 99}); err == nil || err.Error() != "vertex: '99' doesn't exist" {
+		t.Logf("unexpected error: %v\n", err)
+		t.Fail()
+	}
+
+	defer func() {
+		if e, ok := recover().(*ErrUnknownVertex); !ok || e.Key != "LAX" {
+			t.Logf("expected a panic with *ErrUnknownVertex, got: %v\n", e)
+			t.Fail()
+		}
+	}()
+	sg.Index("LAX")
+}
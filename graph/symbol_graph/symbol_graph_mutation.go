@@ -0,0 +1,256 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements incremental, name (key) based mutation of
+// symbol graphs and symbol digraphs.
+//
+// id-stability: once a name is assigned a vertex id, that id never
+// changes for the lifetime of the symbol graph. removing a vertex
+// retires its id (tombstoning) instead of compacting the ids of the
+// remaining vertices. a retired id is never handed out again, it
+// stays in the underlying graph as an isolated vertex, and Name(...)
+// reports it as removed. adding a removed name again gives it a fresh
+// id.
+//
+// Save(...) records tombstones, and so preserves ids across a reload.
+// the other writers (jgf, graphml, gexf, dot) have no way of
+// expressing them, and compact the removed vertices out instead.
+//
+package symbol_graph
+
+import (
+	"github.com/anupamk/common-utilz/graph"
+)
+
+//
+// add a vertex named 'name' to the symbol-graph if it isn't already
+// there, and return its id
+//
+func (sg *SymbolGraphOf[K]) AddVertex(name K) (v int32) {
+	v, added := sg.add_symbol(name)
+	if added {
+		sg.sym_graph.AddVertex()
+	}
+
+	return
+}
+
+//
+// add an edge between vertices named 'a' and 'b', adding the vertices
+// themselves if required. if the edge is rejected by the underlying
+// graph's edge policy, the error is returned, and vertices added for
+// it are taken out again.
+//
+func (sg *SymbolGraphOf[K]) AddEdge(a, b K) (err error) {
+	V := sg.sym_graph.V()
+
+	if err = sg.sym_graph.AddEdge(sg.AddVertex(a), sg.AddVertex(b)); err != nil {
+		for v := sg.sym_graph.V() - 1; v >= V; v-- {
+			sg.sym_graph.RemoveVertex(v)
+		}
+		sg.drop_symbols(V)
+	}
+
+	return
+}
+
+//
+// remove one edge between vertices named 'a' and 'b', and return true
+// if there was such an edge
+//
+func (sg *SymbolGraphOf[K]) RemoveEdge(a, b K) bool {
+	v, vok := sg.sym_table[a]
+	w, wok := sg.sym_table[b]
+
+	return vok && wok && sg.sym_graph.RemoveEdge(v, w)
+}
+
+//
+// remove the vertex named 'name' along with all its edges, and retire
+// its id. returns false if there is no such vertex.
+//
+func (sg *SymbolGraphOf[K]) RemoveVertex(name K) bool {
+	v, ok := sg.remove_symbol(name)
+	if !ok {
+		return false
+	}
+
+	sg.sym_graph.ClearVertex(v)
+	return true
+}
+
+//
+// add a vertex named 'name' to the symbol-digraph if it isn't already
+// there, and return its id
+//
+func (sg *SymbolDigraphOf[K]) AddVertex(name K) (v int32) {
+	v, added := sg.add_symbol(name)
+	if added {
+		sg.sym_graph.AddVertex()
+	}
+
+	return
+}
+
+//
+// add an edge from vertex named 'a' to the one named 'b', adding the
+// vertices themselves if required. if the edge is rejected by the
+// underlying digraph's edge policy, the error is returned, and
+// vertices added for it are taken out again.
+//
+func (sg *SymbolDigraphOf[K]) AddEdge(a, b K) (err error) {
+	V := sg.sym_graph.V()
+
+	if err = sg.sym_graph.AddEdge(sg.AddVertex(a), sg.AddVertex(b)); err != nil {
+		for v := sg.sym_graph.V() - 1; v >= V; v-- {
+			sg.sym_graph.RemoveVertex(v)
+		}
+		sg.drop_symbols(V)
+	}
+
+	return
+}
+
+//
+// remove one edge from vertex named 'a' to the one named 'b', and
+// return true if there was such an edge
+//
+func (sg *SymbolDigraphOf[K]) RemoveEdge(a, b K) bool {
+	v, vok := sg.sym_table[a]
+	w, wok := sg.sym_table[b]
+
+	return vok && wok && sg.sym_graph.RemoveEdge(v, w)
+}
+
+//
+// remove the vertex named 'name' along with all its incoming and
+// outgoing edges, and retire its id. returns false if there is no
+// such vertex. finding the incoming edges requires a scan of the
+// entire digraph.
+//
+func (sg *SymbolDigraphOf[K]) RemoveVertex(name K) bool {
+	v, ok := sg.remove_symbol(name)
+	if !ok {
+		return false
+	}
+
+	sg.sym_graph.ClearVertex(v)
+	return true
+}
+
+//
+// private unexported stuff
+//
+
+// add 'name' to the symbol tables unless it's already there
+func (st *symbol_table_t[K]) add_symbol(name K) (v int32, added bool) {
+	if v, ok := st.sym_table[name]; ok {
+		return v, false
+	}
+
+	v = int32(len(st.keys))
+	st.sym_table[name] = v
+	st.keys = append(st.keys, name)
+
+	return v, true
+}
+
+//
+// undo add_symbol(...) for all the names with ids >= 'n'. these are
+// never handed out, so the ids aren't retired.
+//
+func (st *symbol_table_t[K]) drop_symbols(n int32) {
+	for _, name := range st.keys[n:] {
+		delete(st.sym_table, name)
+	}
+
+	st.keys = st.keys[:n]
+}
+
+// forget 'name', leaving a tombstone in its place in the keys
+func (st *symbol_table_t[K]) remove_symbol(name K) (v int32, ok bool) {
+	if v, ok = st.sym_table[name]; ok {
+		var none K
+
+		delete(st.sym_table, name)
+		st.keys[v] = none
+	}
+
+	return
+}
+
+//
+// ids of vertices which haven't been removed, and whether that is all
+// of them
+//
+func (st *symbol_table_t[K]) live_vertices() (live []int32, all bool) {
+	live = make([]int32, 0, len(st.sym_table))
+
+	for v, name := range st.keys {
+		if id, ok := st.sym_table[name]; ok && id == int32(v) {
+			live = append(live, int32(v))
+		}
+	}
+
+	return live, len(live) == len(st.keys)
+}
+
+//
+// the graph, and keys with removed vertices compacted out, for writers
+// that can't represent tombstones. 'vmap' is nil if nothing was
+// removed, and the graph is returned as is.
+//
+func (sg *SymbolGraphOf[K]) live_graph() (g *graph.Graph, keys []K, vmap *graph.SubgraphMap) {
+	live, all := sg.live_vertices()
+	if all {
+		return sg.sym_graph, sg.keys, nil
+	}
+
+	g, vmap, _ = sg.sym_graph.InducedSubgraph(live)
+	return g, live_keys(sg.keys, live), vmap
+}
+
+func (sg *SymbolDigraphOf[K]) live_graph() (g *graph.Digraph, keys []K, vmap *graph.SubgraphMap) {
+	live, all := sg.live_vertices()
+	if all {
+		return sg.sym_graph, sg.keys, nil
+	}
+
+	g, vmap, _ = sg.sym_graph.InducedSubgraph(live)
+	return g, live_keys(sg.keys, live), vmap
+}
+
+func live_keys[K comparable](keys []K, live []int32) (live_keys []K) {
+	live_keys = make([]K, len(live))
+	for i, v := range live {
+		live_keys[i] = keys[v]
+	}
+
+	return
+}
@@ -0,0 +1,259 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for symbol graph mutation
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"github.com/anupamk/common-utilz/graph"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSymbolGraphMutation(t *testing.T) {
+	sg := New()
+	sg.AddEdge("JFK", "ORD")
+	sg.AddEdge("ORD", "DEN")
+	sg.AddEdge("JFK", "ATL")
+
+	if sg.AddVertex("ORD") != 1 || sg.G().V() != 4 || sg.G().E() != 3 {
+		t.Logf("unexpected symbol graph:\n%s\n", sg)
+		t.FailNow()
+	}
+
+	// ids are stable across removal, and never reused
+	if !sg.RemoveVertex("ORD") || sg.RemoveVertex("ORD") || sg.Contains("ORD") {
+		t.Logf("unexpected vertex removal result\n")
+		t.Fail()
+	}
+
+	if _, err := sg.Name(1); err == nil {
+		t.Logf("removed vertex still has a name\n")
+		t.Fail()
+	}
+
+	if sg.Index("DEN") != 2 || sg.Index("ATL") != 3 || sg.G().E() != 1 || len(sg.G().Adj(1)) != 0 {
+		t.Logf("unexpected symbol graph after removal:\n%s\n", sg)
+		t.Fail()
+	}
+
+	if v := sg.AddVertex("ORD"); v != 4 {
+		t.Logf("expected re-added vertex to get id: 4, got: %d\n", v)
+		t.Fail()
+	}
+
+	if !sg.RemoveEdge("ATL", "JFK") || sg.RemoveEdge("ATL", "JFK") || sg.RemoveEdge("LAX", "JFK") || sg.G().E() != 0 {
+		t.Logf("unexpected edge removal result:\n%s\n", sg)
+		t.Fail()
+	}
+}
+
+func TestSymbolDigraphMutation(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n"
+
+	sg, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	V := sg.G().V()
+
+	sg.AddEdge("PHX", "ORD")
+	sg.AddEdge("LAX", "ORD")
+
+	if sg.G().V() != V+1 || sg.Index("LAX") != V {
+		t.Logf("unexpected symbol digraph:\n%s\n", sg)
+		t.FailNow()
+	}
+
+	// incoming, and outgoing edges go along with the vertex
+	if !sg.RemoveVertex("ORD") || sg.G().E() != 3 {
+		t.Logf("unexpected symbol digraph after removal:\n%s\n", sg)
+		t.Fail()
+	}
+
+	for v := int32(0); v < sg.G().V(); v++ {
+		for _, w := range sg.G().Adj(v) {
+			if _, err := sg.Name(w); err != nil {
+				t.Logf("edge %d -> %d leads to a removed vertex\n", v, w)
+				t.Fail()
+			}
+		}
+	}
+}
+
+//
+// edges rejected by the edge policy leave the symbol graph as it was
+//
+func TestSymbolGraphEdgePolicy(t *testing.T) {
+	sg := New()
+	sg.G().SetEdgePolicy(graph.SimpleGraph)
+
+	if err := sg.AddEdge("JFK", "ORD"); err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if err := sg.AddEdge("ORD", "JFK"); !errors.Is(err, graph.ErrParallelEdge) || sg.G().E() != 1 {
+		t.Logf("expected a parallel edge error, got: %v\n", err)
+		t.Fail()
+	}
+
+	if err := sg.AddEdge("LAX", "LAX"); !errors.Is(err, graph.ErrSelfLoop) || sg.Contains("LAX") || sg.G().V() != 2 {
+		t.Logf("unexpected self-loop result: %v\n%s\n", err, sg)
+		t.Fail()
+	}
+
+	// ids of rejected vertices are handed out again
+	if v := sg.AddVertex("LAX"); v != 2 {
+		t.Logf("expected vertex id: 2, got: %d\n", v)
+		t.Fail()
+	}
+
+	dg := NewDigraph()
+	dg.G().SetEdgePolicy(graph.RejectSelfLoops)
+
+	if err := dg.AddEdge("JFK", "JFK"); !errors.Is(err, graph.ErrSelfLoop) || dg.Contains("JFK") || dg.G().V() != 0 {
+		t.Logf("unexpected self-loop result: %v\n%s\n", err, dg)
+		t.Fail()
+	}
+
+	if err := dg.AddEdge("JFK", "ORD"); err != nil || dg.AddEdge("JFK", "ORD") != nil || dg.G().E() != 2 {
+		t.Logf("unexpected digraph: %v\n%s\n", err, dg)
+		t.Fail()
+	}
+}
+
+//
+// removed vertices must not show up in exported graphs, and reloading
+// an export must give back the same named adjacency
+//
+func TestExportAfterRemoval(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n" +
+		"ORD PHX\nATL HOU\nDEN PHX\nPHX LAX\nJFK ORD\nDEN LAS\n"
+	removed := []string{"ORD", "PHX"}
+
+	named_edges := func(g interface {
+		V() int32
+		Adj(int32) []int32
+	}, name func(int32) (string, error)) (edges []string) {
+		for v := int32(0); v < g.V(); v++ {
+			vname, err := name(v)
+			if err != nil {
+				continue
+			}
+			for _, w := range g.Adj(v) {
+				wname, _ := name(w)
+				edges = append(edges, vname+"-"+wname)
+			}
+		}
+		return
+	}
+
+	sg, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	dg, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	for _, name := range removed {
+		sg.RemoveVertex(name)
+		dg.RemoveVertex(name)
+	}
+
+	graph_formats := []struct {
+		name  string
+		write func(*SymbolGraph, io.Writer) error
+		load  func(io.Reader) (*SymbolGraph, error)
+	}{
+		{"json", (*SymbolGraph).WriteJSON, LoadFromJSON},
+		{"graphml", (*SymbolGraph).WriteGraphML, LoadFromGraphML},
+		{"gexf", (*SymbolGraph).WriteGEXF, LoadFromGEXF},
+		{"dot", func(sg *SymbolGraph, w io.Writer) error { return sg.WriteDot(w, nil) },
+			func(r io.Reader) (*SymbolGraph, error) { return LoadFromDot(bufio.NewReader(r)) }},
+	}
+
+	want := named_edges(sg.G(), sg.Name)
+	for _, f := range graph_formats {
+		var buf bytes.Buffer
+		if err := f.write(sg, &buf); err != nil {
+			t.Logf("format: %s, unexpected error while writing: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		sg2, err := f.load(&buf)
+		if err != nil {
+			t.Logf("format: %s, unexpected error while reading: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		got := named_edges(sg2.G(), sg2.Name)
+		if sg2.G().V() != sg.G().V()-int32(len(removed)) || sg2.Contains("") || sg2.Contains(removed[0]) ||
+			!slice_utils.RelaxedCmpStringSlice(&want, &got) {
+			t.Logf("format: %s, want: %v, got: %v\n", f.name, want, got)
+			t.Fail()
+		}
+	}
+
+	digraph_formats := []struct {
+		name  string
+		write func(*SymbolDigraph, io.Writer) error
+		load  func(io.Reader) (*SymbolDigraph, error)
+	}{
+		{"json", (*SymbolDigraph).WriteJSON, DigraphFromJSON},
+		{"graphml", (*SymbolDigraph).WriteGraphML, DigraphFromGraphML},
+		{"gexf", (*SymbolDigraph).WriteGEXF, DigraphFromGEXF},
+		{"dot", func(dg *SymbolDigraph, w io.Writer) error { return dg.WriteDot(w, nil) },
+			func(r io.Reader) (*SymbolDigraph, error) { return DigraphFromDot(bufio.NewReader(r)) }},
+	}
+
+	want = named_edges(dg.G(), dg.Name)
+	for _, f := range digraph_formats {
+		var buf bytes.Buffer
+		if err := f.write(dg, &buf); err != nil {
+			t.Logf("format: %s, unexpected error while writing: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		dg2, err := f.load(&buf)
+		if err != nil {
+			t.Logf("format: %s, unexpected error while reading: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		got := named_edges(dg2.G(), dg2.Name)
+		if dg2.G().V() != dg.G().V()-int32(len(removed)) || dg2.Contains("") || dg2.Contains(removed[0]) ||
+			!slice_utils.RelaxedCmpStringSlice(&want, &got) {
+			t.Logf("format: %s, want: %v, got: %v\n", f.name, want, got)
+			t.Fail()
+		}
+	}
+}
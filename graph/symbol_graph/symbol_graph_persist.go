@@ -0,0 +1,348 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements saving symbol graphs (and symbol digraphs)
+// along with their symbol tables, so that vertex ids survive a
+// reload. the format is as following
+//
+//     <line-001>   symbol-graph 1 undirected|directed
+//     <line-002>   number of keys (V)
+//     <line-003>   key of vertex 0, as a quoted go string
+//     ....................
+//     <line-V+2>   key of vertex V-1
+//     <line-V+3>   the underlying graph, as emitted by WriteTo(...)
+//     ....................
+//
+// vertices which have been removed are saved as a bare '-' instead of
+// a quoted key, so that their ids remain retired after the reload.
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	saved_magic     = "symbol-graph"
+	saved_version   = 1
+	saved_file_mode = 0644
+)
+
+//
+// save the symbol-graph to 'w', from where it can be reloaded with
+// LoadSaved(...), with all vertex ids intact
+//
+func (sg *SymbolGraph) Save(w io.Writer) error {
+	return save_symbol_graph(w, &sg.symbol_table_t, sg.sym_graph, false)
+}
+
+func (sg *SymbolDigraph) Save(w io.Writer) error {
+	return save_symbol_graph(w, &sg.symbol_table_t, sg.sym_graph, true)
+}
+
+//
+// save the symbol-graph to the file 'fname'. the file is replaced
+// atomically, so that a crash midway never leaves a partially written
+// file behind.
+//
+func (sg *SymbolGraph) SaveFile(fname string) error {
+	return save_to_file(fname, sg.Save)
+}
+
+func (sg *SymbolDigraph) SaveFile(fname string) error {
+	return save_to_file(fname, sg.Save)
+}
+
+//
+// these functions are called to reload a symbol-graph (or
+// symbol-digraph) saved with Save(...)
+//
+func LoadSaved(src io.Reader) (sg *SymbolGraph, err error) {
+	var st symbol_table_t[string]
+	var g *graph.Graph
+
+	r := bufio.NewReader(src)
+	if st, err = load_symbol_table(r, false); err != nil {
+		return
+	}
+
+	if g, _, err = graph.ParseGraph(r, graph.ParseStrict); err != nil {
+		err = saved_graph_error(err, len(st.keys)+2)
+		return
+	}
+
+	if err = check_saved_graph(g, st); err != nil {
+		return
+	}
+
+	sg = &SymbolGraph{}
+	sg.symbol_table_t, sg.sym_graph = st, g
+
+	return
+}
+
+func DigraphFromSaved(src io.Reader) (sg *SymbolDigraph, err error) {
+	var st symbol_table_t[string]
+	var g *graph.Digraph
+
+	r := bufio.NewReader(src)
+	if st, err = load_symbol_table(r, true); err != nil {
+		return
+	}
+
+	if g, _, err = graph.ParseDigraph(r, graph.ParseStrict); err != nil {
+		err = saved_graph_error(err, len(st.keys)+2)
+		return
+	}
+
+	if err = check_saved_graph(g, st); err != nil {
+		return
+	}
+
+	sg = &SymbolDigraph{}
+	sg.symbol_table_t, sg.sym_graph = st, g
+
+	return
+}
+
+//
+// these are convenience interfaces over LoadSaved(...) and
+// DigraphFromSaved(...) for files (optionally compressed)
+//
+func LoadSavedFile(fname string) (sg *SymbolGraph, err error) {
+	var f io.ReadCloser
+
+	if f, err = graph.OpenFile(fname); err != nil {
+		return
+	}
+	defer f.Close()
+
+	return LoadSaved(f)
+}
+
+func DigraphFromSavedFile(fname string) (sg *SymbolDigraph, err error) {
+	var f io.ReadCloser
+
+	if f, err = graph.OpenFile(fname); err != nil {
+		return
+	}
+	defer f.Close()
+
+	return DigraphFromSaved(f)
+}
+
+//
+// private unexported stuff
+//
+
+func save_symbol_graph(dst io.Writer, st *symbol_table_t[string], g io.WriterTo, directed bool) (err error) {
+	kind := "undirected"
+	if directed {
+		kind = "directed"
+	}
+
+	w := bufio.NewWriter(dst)
+	fmt.Fprintf(w, "%s %d %s\n%d\n", saved_magic, saved_version, kind, len(st.keys))
+
+	for v, key := range st.keys {
+		if id, ok := st.sym_table[key]; !ok || id != int32(v) {
+			w.WriteString("-\n")
+			continue
+		}
+
+		w.WriteString(strconv.Quote(key))
+		w.WriteByte('\n')
+	}
+
+	if _, err = g.WriteTo(w); err != nil {
+		return
+	}
+
+	return w.Flush()
+}
+
+//
+// write to a temporary file next to 'fname', and move it in place
+// once it is on disk. an existing 'fname' keeps its permissions, a new
+// one gets saved_file_mode.
+//
+func save_to_file(fname string, save func(io.Writer) error) (err error) {
+	var f *os.File
+
+	var mode os.FileMode = saved_file_mode
+	if fi, stat_err := os.Stat(fname); stat_err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	if f, err = os.CreateTemp(filepath.Dir(fname), filepath.Base(fname)+".tmp-*"); err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if err = save(f); err != nil {
+		f.Close()
+		return
+	}
+
+	if err = f.Chmod(mode); err != nil {
+		f.Close()
+		return
+	}
+
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return
+	}
+
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(f.Name(), fname)
+}
+
+// read the header, and the keys of a saved symbol-graph
+func load_symbol_table(r *bufio.Reader, directed bool) (st symbol_table_t[string], err error) {
+	var line string
+	var version, count int
+	var kind string
+
+	line_no := 1
+	if line, err = read_saved_line(r, line_no); err != nil {
+		return
+	}
+
+	if _, serr := fmt.Sscanf(line, saved_magic+" %d %s", &version, &kind); serr != nil {
+		err = fmt.Errorf("saved: line 1: not a saved symbol graph: %q", line)
+		return
+	}
+
+	if version != saved_version {
+		err = fmt.Errorf("saved: line 1: unsupported version %d, expected %d", version, saved_version)
+		return
+	}
+
+	if want := map[bool]string{false: "undirected", true: "directed"}[directed]; kind != want {
+		err = fmt.Errorf("saved: line 1: graph is %s, expected %s", kind, want)
+		return
+	}
+
+	line_no++
+	if line, err = read_saved_line(r, line_no); err != nil {
+		return
+	}
+
+	if count, err = strconv.Atoi(line); err != nil || count < 0 {
+		err = fmt.Errorf("saved: line 2: malformed key count: %q", line)
+		return
+	}
+
+	st = new_symbol_table[string]()
+	for v := 0; v < count; v++ {
+		var key string
+
+		line_no++
+		if line, err = read_saved_line(r, line_no); err != nil {
+			return
+		}
+
+		// tombstone, keep the id retired
+		if line == "-" {
+			st.keys = append(st.keys, "")
+			continue
+		}
+
+		if key, err = strconv.Unquote(line); err != nil {
+			err = fmt.Errorf("saved: line %d: malformed key: %q", line_no, line)
+			return
+		}
+
+		if _, dup := st.sym_table[key]; dup {
+			err = fmt.Errorf("saved: line %d: duplicate key: %q", line_no, key)
+			return
+		}
+
+		st.sym_table[key] = int32(v)
+		st.keys = append(st.keys, key)
+	}
+
+	return
+}
+
+func read_saved_line(r *bufio.Reader, line_no int) (line string, err error) {
+	line, err = r.ReadString('\n')
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+
+	if err == io.EOF {
+		err = fmt.Errorf("saved: line %d: unexpected end of input", line_no)
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+//
+// line numbers of errors from parsing the graph are relative to its
+// start, and thus off by the 'n' lines of header and keys before it.
+// the *graph.ParseError remains available to errors.As(...).
+//
+func saved_graph_error(err error, n int) error {
+	var pe *graph.ParseError
+
+	if errors.As(err, &pe) && pe.Line > 0 {
+		in_file := *pe
+		in_file.Line += n
+		err = &in_file
+	}
+
+	return fmt.Errorf("saved: %w", err)
+}
+
+// the graph must have exactly one vertex per key
+func check_saved_graph(g graph.GraphOps, st symbol_table_t[string]) error {
+	if g.V() != int32(len(st.keys)) {
+		return fmt.Errorf("saved: graph has %d vertices, expected %d", g.V(), len(st.keys))
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for saving and reloading
+// symbol graphs
+//
+package symbol_graph
+
+import (
+	"bytes"
+	"errors"
+	"github.com/anupamk/common-utilz/graph"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func ExampleSymbolGraph_Save() {
+	sg := New()
+	sg.AddEdge("Pacino, Al", "Heat")
+	sg.AddEdge("De Niro, Robert", "Heat")
+	sg.AddEdge("Pacino, Al", "Serpico")
+	sg.RemoveVertex("Serpico")
+
+	sg.Save(os.Stdout)
+
+	// Output:
+	// symbol-graph 1 undirected
+	// 4
+	// "Pacino, Al"
+	// "Heat"
+	// "De Niro, Robert"
+	// -
+	// 4
+	// 2
+	// 0 1
+	// 1 2
+}
+
+//
+// ids, names and adjacencies must all survive a save and reload, with
+// retired ids staying retired
+//
+func TestSaveAndReload(t *testing.T) {
+	sg := NewDigraph()
+	for _, e := range [][2]string{{"a\tb", "c\nd"}, {"c\nd", "\"e\""}, {"x", "a\tb"}, {"\"e\"", "\"e\""}} {
+		sg.AddEdge(e[0], e[1])
+	}
+	sg.RemoveVertex("x")
+
+	var buf bytes.Buffer
+	if err := sg.Save(&buf); err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	reloaded, err := DigraphFromSaved(&buf)
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if sg.String() != reloaded.String() {
+		t.Logf("expected:\n%s\ngot:\n%s\n", sg, reloaded)
+		t.Fail()
+	}
+
+	if _, err = reloaded.Name(sg.G().V() - 1); err == nil || reloaded.Contains("x") {
+		t.Logf("removed vertex came back to life\n")
+		t.Fail()
+	}
+
+	// a fresh vertex must not reuse the retired id
+	if v := reloaded.AddVertex("y"); v != sg.G().V() {
+		t.Logf("expected new vertex id: %d, got: %d\n", sg.G().V(), v)
+		t.Fail()
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "saved-symbol-graph")
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	sg := New()
+	sg.AddEdge("JFK", "ORD")
+	sg.AddEdge("ORD", "DEN")
+
+	fname := filepath.Join(dir, "routes.sg")
+	for i := 0; i < 2; i++ {
+		if err = sg.SaveFile(fname); err != nil {
+			t.Logf("unexpected error: %s\n", err)
+			t.FailNow()
+		}
+	}
+
+	reloaded, err := LoadSavedFile(fname)
+	if err != nil || reloaded.Index("DEN") != 2 || reloaded.G().E() != 2 {
+		t.Logf("unexpected symbol graph: %v, error: %v\n", reloaded, err)
+		t.Fail()
+	}
+
+	// no temporary files left behind
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Logf("unexpected files in %s: %v\n", dir, entries)
+		t.Fail()
+	}
+
+	// new files get the default mode, existing ones keep theirs
+	if fi, err := os.Stat(fname); err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.Fail()
+	} else if fi.Mode().Perm() != saved_file_mode {
+		t.Logf("unexpected mode: %v\n", fi.Mode())
+		t.Fail()
+	}
+
+	if err = os.Chmod(fname, 0600); err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if err = sg.SaveFile(fname); err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+
+	if fi, err := os.Stat(fname); err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.Fail()
+	} else if fi.Mode().Perm() != 0600 {
+		t.Logf("unexpected mode: %v\n", fi.Mode())
+		t.Fail()
+	}
+}
+
+func TestLoadSavedErrors(t *testing.T) {
+	test_cases := []struct {
+		data   string
+		reason string
+	}{
+		{"", "line 1: unexpected end of input"},
+		{"graph 1 undirected\n", "not a saved symbol graph"},
+		{"symbol-graph 2 undirected\n", "unsupported version 2"},
+		{"symbol-graph 1 directed\n", "graph is directed, expected undirected"},
+		{"symbol-graph 1 undirected\nmany\n", "malformed key count"},
+		{"symbol-graph 1 undirected\n2\n\"a\"\n", "line 4: unexpected end of input"},
+		{"symbol-graph 1 undirected\n2\n\"a\"\nb\n", "line 4: malformed key"},
+		{"symbol-graph 1 undirected\n2\n\"a\"\n\"a\"\n", "line 4: duplicate key"},
+		{"symbol-graph 1 undirected\n2\n\"a\"\n\"b\"\n3\n0\n", "graph has 3 vertices, expected 2"},
+		{"symbol-graph 1 undirected\n2\n\"a\"\n\"b\"\n2\n1\n0 2\n", "line 7: vertex 2 out of range"},
+	}
+
+	for i, tc := range test_cases {
+		_, err := LoadSaved(strings.NewReader(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.reason) {
+			t.Logf("test-case: %d, expected: %s, got: %v\n", i, tc.reason, err)
+			t.Fail()
+		}
+	}
+
+	// problems in the graph are reported at their line in the file
+	data := "symbol-graph 1 directed\n3\n\"a\"\n-\n\"c\"\n3\n2\n0 2\n2 x\n"
+
+	var pe *graph.ParseError
+	if _, err := DigraphFromSaved(strings.NewReader(data)); !errors.As(err, &pe) || pe.Line != 9 {
+		t.Logf("expected a parse error at line 9, got: %v\n", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,309 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements an index over vertex names of string keyed
+// symbol graphs, for case-insensitive, prefix, and approximate
+// (edit-distance bounded) name searches. candidates are always
+// returned best first.
+//
+package symbol_graph
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//
+// how a candidate matched the query
+//
+type MatchKind int
+
+const (
+	MatchFold   MatchKind = iota // same as the query, ignoring case
+	MatchPrefix                  // query is a prefix of the name, ignoring case
+	MatchFuzzy                   // within some edit distance of the query, ignoring case
+)
+
+//
+// a vertex name matching some query. 'Distance' is the number of
+// runes differing in case for MatchFold, number of runes following
+// the prefix for MatchPrefix, and the edit distance for MatchFuzzy.
+//
+type Candidate struct {
+	Name     string
+	Id       int32
+	Kind     MatchKind
+	Distance int
+}
+
+//
+// a read-only snapshot of vertex names of a symbol graph. it doesn't
+// track subsequent additions/removals of vertices, and needs to be
+// rebuilt for that.
+//
+type NameIndex struct {
+	entries []name_entry_t // sorted by folded name, and then name
+}
+
+//
+// this function returns an index over the current vertex names of the
+// symbol graph
+//
+func (sg *SymbolGraph) NameIndex() *NameIndex { return new_name_index(&sg.symbol_table_t) }
+
+func (sg *SymbolDigraph) NameIndex() *NameIndex { return new_name_index(&sg.symbol_table_t) }
+
+//
+// this function returns all names which are the same as 'name' when
+// case is ignored, the ones with fewest case differences first
+//
+func (ni *NameIndex) LookupFold(name string) (matches []Candidate) {
+	query := []rune(name)
+	folded := fold_case(name)
+
+	for i := ni.lower_bound(folded); i < len(ni.entries) && ni.entries[i].folded == folded; i++ {
+		e := &ni.entries[i]
+		matches = append(matches, e.candidate(MatchFold, case_differences(query, []rune(e.name))))
+	}
+
+	return rank_candidates(matches, 0)
+}
+
+//
+// this function returns at most 'limit' (all if limit <= 0) names
+// starting with 'prefix', ignoring case, the shortest ones first
+//
+func (ni *NameIndex) Prefix(prefix string, limit int) (matches []Candidate) {
+	folded := fold_case(prefix)
+	n := len([]rune(folded))
+
+	for i := ni.lower_bound(folded); i < len(ni.entries) && strings.HasPrefix(ni.entries[i].folded, folded); i++ {
+		e := &ni.entries[i]
+		matches = append(matches, e.candidate(MatchPrefix, len(e.runes)-n))
+	}
+
+	return rank_candidates(matches, limit)
+}
+
+//
+// this function returns at most 'limit' (all if limit <= 0) names
+// within 'max_dist' edits (insertions, deletions, substitutions) of
+// 'name', ignoring case, the closest ones first
+//
+func (ni *NameIndex) Fuzzy(name string, max_dist int, limit int) (matches []Candidate) {
+	query := []rune(fold_case(name))
+
+	for i := range ni.entries {
+		e := &ni.entries[i]
+
+		if d, ok := bounded_edit_distance(query, e.runes, max_dist); ok {
+			matches = append(matches, e.candidate(MatchFuzzy, d))
+		}
+	}
+
+	return rank_candidates(matches, limit)
+}
+
+//
+// this function combines all of the above, and returns at most
+// 'limit' (all if limit <= 0) distinct candidates for 'name'. case
+// insensitive matches rank before prefix matches, which rank before
+// fuzzy matches.
+//
+func (ni *NameIndex) Search(name string, max_dist int, limit int) (matches []Candidate) {
+	seen := make(map[int32]bool)
+
+	for _, list := range [][]Candidate{
+		ni.LookupFold(name),
+		ni.Prefix(name, 0),
+		ni.Fuzzy(name, max_dist, 0),
+	} {
+		for _, c := range list {
+			if !seen[c.Id] {
+				seen[c.Id] = true
+				matches = append(matches, c)
+			}
+		}
+	}
+
+	return rank_candidates(matches, limit)
+}
+
+//
+// private unexported stuff
+//
+
+type name_entry_t struct {
+	name   string
+	folded string // case folded name
+	runes  []rune // ...and its runes, for edit distances
+	id     int32
+}
+
+func (e *name_entry_t) candidate(kind MatchKind, distance int) Candidate {
+	return Candidate{Name: e.name, Id: e.id, Kind: kind, Distance: distance}
+}
+
+func new_name_index(st *symbol_table_t[string]) (ni *NameIndex) {
+	ni = &NameIndex{entries: make([]name_entry_t, 0, len(st.sym_table))}
+
+	for name, id := range st.sym_table {
+		folded := fold_case(name)
+		ni.entries = append(ni.entries, name_entry_t{
+			name:   name,
+			folded: folded,
+			runes:  []rune(folded),
+			id:     id,
+		})
+	}
+
+	sort.Slice(ni.entries, func(i, j int) bool {
+		x, y := &ni.entries[i], &ni.entries[j]
+		if x.folded != y.folded {
+			return x.folded < y.folded
+		}
+		return x.name < y.name
+	})
+
+	return
+}
+
+// index of the first entry whose folded name is >= 'folded'
+func (ni *NameIndex) lower_bound(folded string) int {
+	return sort.Search(len(ni.entries), func(i int) bool {
+		return ni.entries[i].folded >= folded
+	})
+}
+
+//
+// map each rune to a canonical case, such that two strings are the
+// same under strings.EqualFold(...) iff their folded versions are
+// the same (modulo a handful of special cases)
+//
+func fold_case(s string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, s)
+}
+
+// number of positions at which 'x' and 'y' differ
+func case_differences(x, y []rune) (n int) {
+	for i := range x {
+		if i >= len(y) || x[i] != y[i] {
+			n += 1
+		}
+	}
+
+	return
+}
+
+//
+// sort candidates by kind, distance, and name (in that order), and
+// trim them down to 'limit' (if > 0)
+//
+func rank_candidates(matches []Candidate, limit int) []Candidate {
+	sort.SliceStable(matches, func(i, j int) bool {
+		x, y := &matches[i], &matches[j]
+
+		switch {
+		case x.Kind != y.Kind:
+			return x.Kind < y.Kind
+		case x.Distance != y.Distance:
+			return x.Distance < y.Distance
+		}
+
+		return x.Name < y.Name
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+//
+// levenshtein distance between 'x' and 'y', if it is <= 'bound'. the
+// computation is abandoned as soon as it becomes clear that the
+// distance is going to exceed 'bound'.
+//
+func bounded_edit_distance(x, y []rune, bound int) (dist int, ok bool) {
+	if bound < 0 || abs_int(len(x)-len(y)) > bound {
+		return
+	}
+
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+		row_min := curr[0]
+
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min_int(prev[j-1]+cost, min_int(prev[j]+1, curr[j-1]+1))
+			row_min = min_int(row_min, curr[j])
+		}
+
+		// distances never decrease from one row to the next
+		if row_min > bound {
+			return
+		}
+
+		prev, curr = curr, prev
+	}
+
+	if dist = prev[len(y)]; dist <= bound {
+		ok = true
+	}
+
+	return
+}
+
+func abs_int(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func min_int(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for vertex name searches
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func create_search_test_graph() *SymbolGraph {
+	data := "Bacon, Kevin/Apollo 13 (1995)\nHanks, Tom/Apollo 13 (1995)\n" +
+		"Hanks, Tom/Big (1988)\nbacon, kevin/Big (1988)\nBacall, Lauren/Key Largo (1948)\n" +
+		"Bogart, Humphrey/Key Largo (1948)\n"
+
+	sg, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), "/")
+	return sg
+}
+
+func candidate_names(matches []Candidate) (names []string) {
+	for _, c := range matches {
+		names = append(names, c.Name)
+	}
+	return
+}
+
+func TestNameIndex(t *testing.T) {
+	sg := create_search_test_graph()
+	ni := sg.NameIndex()
+
+	test_data := []struct {
+		what string
+		got  []Candidate
+		want []string
+	}{
+		{"fold", ni.LookupFold("BACON, KEVIN"), []string{"Bacon, Kevin", "bacon, kevin"}},
+		{"fold-exact", ni.LookupFold("bacon, kevin"), []string{"bacon, kevin", "Bacon, Kevin"}},
+		{"fold-none", ni.LookupFold("Bacon"), nil},
+		{"prefix", ni.Prefix("ba", 0), []string{"Bacon, Kevin", "bacon, kevin", "Bacall, Lauren"}},
+		{"prefix-limit", ni.Prefix("KEY", 1), []string{"Key Largo (1948)"}},
+		{"prefix-none", ni.Prefix("zz", 0), nil},
+		{"fuzzy", ni.Fuzzy("bacn, kevn", 2, 0), []string{"Bacon, Kevin", "bacon, kevin"}},
+		{"fuzzy-bound", ni.Fuzzy("bacn, kevn", 1, 0), nil},
+		{"fuzzy-rank", ni.Fuzzy("Big (1989)", 3, 0), []string{"Big (1988)"}},
+		{"search", ni.Search("big", 2, 0), []string{"Big (1988)"}},
+	}
+
+	for _, td := range test_data {
+		got := candidate_names(td.got)
+		if fmt.Sprint(got) != fmt.Sprint(td.want) {
+			t.Logf("%s: want: %q, got: %q\n", td.what, td.want, got)
+			t.Fail()
+		}
+
+		for _, c := range td.got {
+			if id, _ := sg.Lookup(c.Name); id != c.Id {
+				t.Logf("%s: candidate: %q has id: %d, expected: %d\n", td.what, c.Name, c.Id, id)
+				t.Fail()
+			}
+		}
+	}
+
+	// removed vertices don't show up in a new index
+	sg.RemoveVertex("bacon, kevin")
+	if got := candidate_names(sg.NameIndex().Prefix("bacon", 0)); len(got) != 1 || got[0] != "Bacon, Kevin" {
+		t.Logf("unexpected candidates after removal: %q\n", got)
+		t.Fail()
+	}
+}
+
+func TestBoundedEditDistance(t *testing.T) {
+	test_data := []struct {
+		x, y  string
+		bound int
+		dist  int
+		ok    bool
+	}{
+		{"kitten", "sitting", 3, 3, true},
+		{"kitten", "sitting", 2, 0, false},
+		{"", "abc", 3, 3, true},
+		{"abc", "abc", 0, 0, true},
+		{"flaw", "lawn", 2, 2, true},
+		{"naïve", "naive", 1, 1, true},
+	}
+
+	for _, td := range test_data {
+		dist, ok := bounded_edit_distance([]rune(td.x), []rune(td.y), td.bound)
+		if ok != td.ok || (ok && dist != td.dist) {
+			t.Logf("%q, %q (bound: %d): want: %d/%v, got: %d/%v\n", td.x, td.y, td.bound, td.dist, td.ok, dist, ok)
+			t.Fail()
+		}
+	}
+}
+
+func ExampleNameIndex_Search() {
+	sg := create_search_test_graph()
+
+	for _, query := range []string{"bogart", "bacon kevin"} {
+		for _, c := range sg.NameIndex().Search(query, 2, 3) {
+			fmt.Println(c.Name, c.Distance)
+		}
+	}
+	// Output:
+	// Bogart, Humphrey 10
+	// Bacon, Kevin 1
+	// bacon, kevin 1
+}
@@ -33,7 +33,12 @@
 package symbol_graph
 
 import (
+	"bufio"
+	"compress/gzip"
 	"github.com/anupamk/common-utilz/slice_utils"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -148,3 +153,68 @@ func TestNameAdj(t *testing.T) {
 
 	return
 }
+
+//
+// WriteTo(...) must stream exactly what String() returns, and report
+// the number of bytes written
+//
+func TestSymbolGraphWriteTo(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n"
+
+	g, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	dg, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	var gsb, dgsb strings.Builder
+	gn, gerr := g.WriteTo(&gsb)
+	dgn, dgerr := dg.WriteTo(&dgsb)
+
+	if gerr != nil || gsb.String() != g.String() || gn != int64(gsb.Len()) {
+		t.Logf("graph: error: %v, byte-count: %d, output:\n%s\n", gerr, gn, gsb.String())
+		t.Fail()
+	}
+
+	if dgerr != nil || dgsb.String() != dg.String() || dgn != int64(dgsb.Len()) {
+		t.Logf("digraph: error: %v, byte-count: %d, output:\n%s\n", dgerr, dgn, dgsb.String())
+		t.Fail()
+	}
+
+	if !strings.HasPrefix(gsb.String(), "symbol-table: ") || !strings.Contains(gsb.String(), "\ngraph\n8 vertices, 6 edges\n") {
+		t.Logf("graph: unexpected output:\n%s\n", gsb.String())
+		t.Fail()
+	}
+}
+
+//
+// compressed symbol graph files are decompressed on the fly
+//
+func TestLoadCompressedFile(t *testing.T) {
+	data := "JFK MCO\nORD DEN\nORD HOU\nDFW PHX\nJFK ATL\nORD DFW\n"
+
+	f, err := ioutil.TempFile("", "symgraph-gzip")
+	if err != nil {
+		t.Logf("unexpected error: %s\n", err)
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+
+	zw := gzip.NewWriter(f)
+	zw.Write([]byte(data))
+	zw.Close()
+	f.Close()
+
+	g1, _ := LoadFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+	dg1, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), " ")
+
+	g2, gerr := LoadFromFile(f.Name(), " ")
+	dg2, dgerr := DigraphFromFile(f.Name(), " ")
+
+	if gerr != nil || g1.String() != g2.String() {
+		t.Logf("graph: error: %v, got:\n%s\n", gerr, g2)
+		t.Fail()
+	}
+
+	if dgerr != nil || dg1.String() != dg2.String() {
+		t.Logf("digraph: error: %v, got:\n%s\n", dgerr, dg2)
+		t.Fail()
+	}
+}
@@ -0,0 +1,165 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements reading and writing symbol graphs in the
+// graphml and gexf formats, vertex names are carried as node labels
+//
+package symbol_graph
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"io"
+)
+
+//
+// these functions emit the symbol-graph in graphml and gexf formats,
+// with vertex names as node labels. removed vertices are left out, and
+// the remaining ones renumbered.
+//
+func (sg *SymbolGraph) WriteGraphML(w io.Writer) error {
+	g, keys, _ := sg.live_graph()
+	return g.WriteGraphML(w, keys)
+}
+
+func (sg *SymbolGraph) WriteGEXF(w io.Writer) error {
+	g, keys, _ := sg.live_graph()
+	return g.WriteGEXF(w, keys)
+}
+
+//
+// these functions emit the symbol-digraph in graphml and gexf
+// formats, with vertex names as node labels. removed vertices are
+// left out, and the remaining ones renumbered.
+//
+func (sg *SymbolDigraph) WriteGraphML(w io.Writer) error {
+	g, keys, _ := sg.live_graph()
+	return g.WriteGraphML(w, keys)
+}
+
+func (sg *SymbolDigraph) WriteGEXF(w io.Writer) error {
+	g, keys, _ := sg.live_graph()
+	return g.WriteGEXF(w, keys)
+}
+
+//
+// these functions are called to create a symbol-graph from its
+// graphml or gexf definition. every node must carry a distinct label,
+// which becomes the vertex name.
+//
+func LoadFromGraphML(src io.Reader) (sg *SymbolGraph, err error) {
+	g, labels, err := graph.LoadFromGraphML(src)
+	if err != nil {
+		return
+	}
+
+	return symbol_graph_from_labels(g, labels)
+}
+
+func LoadFromGEXF(src io.Reader) (sg *SymbolGraph, err error) {
+	g, labels, err := graph.LoadFromGEXF(src)
+	if err != nil {
+		return
+	}
+
+	return symbol_graph_from_labels(g, labels)
+}
+
+//
+// these functions are called to create a symbol-digraph from its
+// graphml or gexf definition. every node must carry a distinct label,
+// which becomes the vertex name.
+//
+func DigraphFromGraphML(src io.Reader) (sg *SymbolDigraph, err error) {
+	g, labels, err := graph.LoadDigraphFromGraphML(src)
+	if err != nil {
+		return
+	}
+
+	return symbol_digraph_from_labels(g, labels)
+}
+
+func DigraphFromGEXF(src io.Reader) (sg *SymbolDigraph, err error) {
+	g, labels, err := graph.LoadDigraphFromGEXF(src)
+	if err != nil {
+		return
+	}
+
+	return symbol_digraph_from_labels(g, labels)
+}
+
+//
+// private unexported stuff
+//
+
+func symbol_graph_from_labels(g *graph.Graph, labels []string) (sg *SymbolGraph, err error) {
+	if err = check_labels(labels, g.V()); err != nil {
+		return
+	}
+
+	sg = &SymbolGraph{}
+	sg.sym_graph = g
+	sg.sym_table, sg.keys = symbol_tables_from_names(labels)
+
+	return
+}
+
+func symbol_digraph_from_labels(g *graph.Digraph, labels []string) (sg *SymbolDigraph, err error) {
+	if err = check_labels(labels, g.V()); err != nil {
+		return
+	}
+
+	sg = &SymbolDigraph{}
+	sg.sym_graph = g
+	sg.sym_table, sg.keys = symbol_tables_from_names(labels)
+
+	return
+}
+
+//
+// vertex names must be present, and distinct. graphs without vertices
+// don't have any labels either, which is fine.
+//
+func check_labels(labels []string, V int32) (err error) {
+	if labels == nil && V > 0 {
+		err = fmt.Errorf("error: nodes don't have labels, no vertex names")
+		return
+	}
+
+	seen := make(map[string]bool, len(labels))
+	for i, l := range labels {
+		if seen[l] {
+			err = fmt.Errorf("error: node %d, duplicate label: '%s'", i, l)
+			return
+		}
+		seen[l] = true
+	}
+
+	return
+}
@@ -0,0 +1,168 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// this package implements the symbol graph where vertex names are
+// strings and number of edges/vertices are implicitly defined. this
+// is more typical of real-world (tm) graph applications
+//
+// this file implements the testing routine for graphml/gexf
+// reading/writing
+//
+package symbol_graph
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/anupamk/common-utilz/slice_utils"
+	"io"
+	"strings"
+	"testing"
+)
+
+// named adjacency of a vertex in a symbol-digraph
+func digraph_named_adj(sg *SymbolDigraph, name string) (adj []string) {
+	for _, w := range sg.G().Adj(sg.Index(name)) {
+		wname, _ := sg.Name(w)
+		adj = append(adj, wname)
+	}
+
+	return
+}
+
+//
+// write a symbol-digraph in graphml and gexf, read it back and
+// compare named adjacencies
+//
+func TestXMLRoundTrip(t *testing.T) {
+	data := "Algorithms/Theoretical CS/Databases/Scientific Computing\n" +
+		"Introduction to CS/Advanced Programming/Algorithms\n" +
+		"Advanced Programming/Scientific Computing\n" +
+		"Scientific Computing/Computational Biology\n" +
+		"Theoretical CS/Computational Biology/Artificial Intelligence\n" +
+		"Linear Algebra/Theoretical CS\n" +
+		"Calculus/Linear Algebra\n" +
+		"Artificial Intelligence/Neural Networks/Robotics/Machine Learning\n" +
+		"Machine Learning/Neural Networks\n"
+
+	sg1, _ := DigraphFromReader(bufio.NewReader(strings.NewReader(data)), "/")
+
+	formats := []struct {
+		name  string
+		write func(*bytes.Buffer) error
+		read  func(*bytes.Buffer) (*SymbolDigraph, error)
+	}{
+		{"graphml",
+			func(b *bytes.Buffer) error { return sg1.WriteGraphML(b) },
+			func(b *bytes.Buffer) (*SymbolDigraph, error) { return DigraphFromGraphML(b) }},
+		{"gexf",
+			func(b *bytes.Buffer) error { return sg1.WriteGEXF(b) },
+			func(b *bytes.Buffer) (*SymbolDigraph, error) { return DigraphFromGEXF(b) }},
+	}
+
+	for _, f := range formats {
+		var buf bytes.Buffer
+
+		if err := f.write(&buf); err != nil {
+			t.Logf("%s: unexpected error while writing: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		sg2, err := f.read(&buf)
+		if err != nil {
+			t.Logf("%s: unexpected error while reading: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		if sg1.G().V() != sg2.G().V() || sg1.G().E() != sg2.G().E() {
+			t.Logf("%s: vertex/edge count mismatch\n", f.name)
+			t.Fail()
+			continue
+		}
+
+		for v := int32(0); v < sg1.G().V(); v++ {
+			name, _ := sg1.Name(v)
+			want, got := digraph_named_adj(sg1, name), digraph_named_adj(sg2, name)
+
+			if !slice_utils.CmpStringSlice(&want, &got) {
+				t.Logf("%s: vertex: %s, want-adj: %v, got-adj: %v\n", f.name, name, want, got)
+				t.Fail()
+			}
+		}
+	}
+}
+
+//
+// empty symbol graphs have no labels to write, and must still load
+// back
+//
+func TestEmptyRoundTrip(t *testing.T) {
+	formats := []struct {
+		name  string
+		write func(*SymbolGraph, io.Writer) error
+		load  func(io.Reader) (*SymbolGraph, error)
+	}{
+		{"json", (*SymbolGraph).WriteJSON, LoadFromJSON},
+		{"graphml", (*SymbolGraph).WriteGraphML, LoadFromGraphML},
+		{"gexf", (*SymbolGraph).WriteGEXF, LoadFromGEXF},
+	}
+
+	for _, f := range formats {
+		var buf bytes.Buffer
+
+		if err := f.write(New(), &buf); err != nil {
+			t.Logf("%s: unexpected error while writing: %s\n", f.name, err)
+			t.Fail()
+			continue
+		}
+
+		if sg, err := f.load(&buf); err != nil || sg.G().V() != 0 {
+			t.Logf("%s: unexpected empty graph: %v, error: %v\n", f.name, sg, err)
+			t.Fail()
+		}
+	}
+
+	var buf bytes.Buffer
+	NewDigraph().WriteGraphML(&buf)
+	if dg, err := DigraphFromGraphML(&buf); err != nil || dg.G().V() != 0 {
+		t.Logf("graphml: unexpected empty digraph: %v, error: %v\n", dg, err)
+		t.Fail()
+	}
+}
+
+func TestXMLLabelErrors(t *testing.T) {
+	unlabelled := `<gexf version="1.3"><graph defaultedgetype="undirected">
+<nodes><node id="0"/><node id="1"/></nodes></graph></gexf>`
+	duplicate := `<gexf version="1.3"><graph defaultedgetype="undirected">
+<nodes><node id="0" label="a"/><node id="1" label="a"/></nodes></graph></gexf>`
+
+	for _, doc := range []string{unlabelled, duplicate} {
+		if _, err := LoadFromGEXF(strings.NewReader(doc)); err == nil {
+			t.Logf("expected error loading: %s\n", doc)
+			t.Fail()
+		}
+	}
+}
@@ -30,9 +30,8 @@ package graph
 
 import (
 	"bufio"
-	"fmt"
 	"io"
-	"os"
+	"strings"
 )
 
 // a list of vertices making up the graph
@@ -44,9 +43,12 @@ type vertex_list_t []int32
 // of processing
 //
 type Graph struct {
-	v   int32
-	e   int32
-	adj []vertex_list_t
+	v      int32
+	e      int32
+	adj    []vertex_list_t
+	attrs  *Attributes // nil, until attributes are used
+	policy EdgePolicy  // edges rejected by AddEdge(...)
+	grow   bool        // AddEdge(...) adds missing vertices
 }
 
 //
@@ -68,9 +70,26 @@ func (G *Graph) Adj(v int32) []int32 { return G.adj[v] }
 //
 // in a graph G, add an edge between vertices 'v' and 'w'. for
 // undirected graphs, this operation adds v-w, and w-v edges as
-// well
+// well. edges not allowed by the graph's edge policy are rejected
+// with ErrParallelEdge or ErrSelfLoop. in auto-grow mode, vertices
+// >= V are added once the edge is accepted, otherwise they are
+// rejected with ErrBadVertex.
 //
-func (G *Graph) AddEdge(v, w int32) {
+func (G *Graph) AddEdge(v, w int32) (err error) {
+	var bound int32
+
+	if bound, err = edge_bound(G.v, v, w, G.grow); err != nil {
+		return
+	}
+
+	if G.policy != 0 {
+		if err = G.policy.check(G, v, w, false); err != nil {
+			return
+		}
+	}
+
+	G.grow_to(bound)
+
 	V := &G.adj[v]
 	W := &G.adj[w]
 
@@ -82,64 +101,158 @@ func (G *Graph) AddEdge(v, w int32) {
 	return
 }
 
-func (G *Graph) String() string { return graph_stringifier(G) }
+//
+// set the kinds of edges rejected by subsequent AddEdge(...)
+// invokations. edges already in the graph aren't affected.
+//
+func (G *Graph) SetEdgePolicy(policy EdgePolicy) { G.policy = policy }
+func (G *Graph) EdgePolicy() EdgePolicy          { return G.policy }
 
 //
-// this function emits the graph structure in a format suitable for
-// subsequent loading from LoadFromXXX(...) invokation
+// in auto-grow mode, AddEdge(...) adds vertices up to the larger of
+// its endpoints (if required), so that the graph can be built without
+// knowing the number of vertices up front.
 //
-func (G *Graph) Serialize() string {
-	str := ""
-
-	// vertex and edge count
-	str += fmt.Sprintf("%d\n", G.V())
-	str += fmt.Sprintf("%d\n", G.E())
-
-	// vertex-specific adjacency-list dump
-	for v := int32(0); v < G.V(); v++ {
-		for _, w := range G.Adj(v) {
-			// for undirected graphs, don't dump both v-w,
-			// and w-v edges
-			if v > w {
-				continue
-			}
-
-			str += fmt.Sprintf("%d %d\n", v, w)
-		}
+func (G *Graph) SetAutoGrow(yesno bool) { G.grow = yesno }
+func (G *Graph) AutoGrow() bool         { return G.grow }
+
+//
+// is there an edge between vertices 'v' and 'w', and if so how many.
+// out of range vertices have no edges.
+//
+func (G *Graph) HasEdge(v, w int32) bool       { return has_edge(G, v, w, false) }
+func (G *Graph) Multiplicity(v, w int32) int32 { return multiplicity(G, v, w, false) }
+
+//
+// add a new, isolated vertex to the graph, and return its id i.e. the
+// old vertex count
+//
+func (G *Graph) AddVertex() (v int32) {
+	v = G.v
+
+	G.adj = append(G.adj, nil)
+	G.v += 1
+
+	return
+}
+
+// add vertices, so that there are at least 'V' of them
+func (G *Graph) grow_to(V int32) {
+	if V > G.v {
+		G.adj = append(G.adj, make([]vertex_list_t, V-G.v)...)
+		G.v = V
+	}
+}
+
+//
+// remove one edge between vertices 'v' and 'w' (there could be more
+// than one), and return true if there was such an edge.
+//
+func (G *Graph) RemoveEdge(v, w int32) bool {
+	if !in_range(G.v, v, w) || !G.adj[v].remove(w) {
+		return false
 	}
+	G.adj[w].remove(v)
+	G.attrs.edge_removed(v, w, G.adj[v])
 
-	return str
+	G.e -= 1
+	return true
 }
 
 //
-// this function is called to create a graph from it's serialized
-// definition.
+// remove all the edges between vertices 'v' and 'w', and return their
+// number. there aren't any when either vertex is out of range.
 //
-func LoadFromReader(src *bufio.Reader) (new_graph *Graph, err error) {
-	var V, E int32
-	var edges [][2]int32
+func (G *Graph) RemoveEdges(v, w int32) (n int32) {
+	if !in_range(G.v, v, w) {
+		return
+	}
 
-	V, E, edges, err = parse_graph_datafile(src)
-	if err != nil && err != io.EOF {
-		goto all_done
+	if n = G.adj[v].remove_all(w); n == 0 {
+		return
 	}
 
-	// create the graph, and setup the connections
-	new_graph = New(V)
-	for i := int32(0); i < E; i++ {
-		v, w := edges[i][0], edges[i][1]
+	if v != w {
+		G.adj[w].remove_all(v)
+	} else {
+		n /= 2
+	}
+	G.attrs.edge_removed(v, w, G.adj[v])
 
-		// skip edges which are obviouzly bogus
-		if V < v || v < 0 || V < w || w < 0 {
-			fmt.Printf("skipping bogus connection: %d %d\n", v, w)
-			continue
-		}
-		new_graph.AddEdge(w, v)
+	G.e -= n
+	return
+}
+
+//
+// remove all the edges incident on vertex 'v', leaving it isolated,
+// and return their number. out of range vertices are rejected with
+// ErrBadVertex.
+//
+func (G *Graph) ClearVertex(v int32) (n int32, err error) {
+	if err = check_vertex(G.v, v); err != nil {
+		return
+	}
+
+	for len(G.adj[v]) > 0 {
+		n += G.RemoveEdges(v, G.adj[v][0])
 	}
 
-	ReverseAdjList(new_graph)
+	return
+}
+
+//
+// remove vertex 'v' along with all its edges, and return the number
+// of edges removed. vertices after 'v' are renumbered down by one,
+// and their attributes (if any) go along with them. out of range
+// vertices are rejected with ErrBadVertex.
+//
+func (G *Graph) RemoveVertex(v int32) (n int32, err error) {
+	var old_to_new []int32
 
-all_done:
+	if n, err = G.ClearVertex(v); err != nil {
+		return
+	}
+	G.adj, old_to_new = remove_isolated_vertex(G.adj, v)
+	G.attrs = G.attrs.remap(old_to_new)
+	G.v -= 1
+
+	return
+}
+
+func (G *Graph) String() string { return graph_stringifier(G) }
+
+//
+// this function emits the graph structure in a format suitable for
+// subsequent loading from LoadFromXXX(...) invokation
+//
+func (G *Graph) Serialize() string {
+	var sb strings.Builder
+
+	G.WriteTo(&sb)
+	return sb.String()
+}
+
+//
+// streaming version of Serialize(...), which implements io.WriterTo
+//
+func (G *Graph) WriteTo(w io.Writer) (n int64, err error) {
+	return write_edge_list(w, G, false)
+}
+
+//
+// streaming version of String(...)
+//
+func (G *Graph) DumpTo(w io.Writer) (n int64, err error) {
+	return write_adjacency(w, G)
+}
+
+//
+// this function is called to create a graph from it's serialized
+// definition. malformed, or out-of-range edges are skipped, use
+// ParseGraph(...) for the details, or for strict parsing.
+//
+func LoadFromReader(src *bufio.Reader) (new_graph *Graph, err error) {
+	new_graph, _, err = ParseGraph(src, ParseLenient)
 	return
 }
 
@@ -149,9 +262,9 @@ all_done:
 // 'fname'
 //
 func LoadFromFile(fname string) (g *Graph, err error) {
-	var f *os.File
+	var f io.ReadCloser
 
-	if f, err = os.Open(fname); err != nil {
+	if f, err = OpenFile(fname); err != nil {
 		return nil, err
 	}
 	defer f.Close()
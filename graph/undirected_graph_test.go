@@ -26,6 +26,8 @@
 package graph
 
 import (
+	"errors"
+	"math"
 	"testing"
 )
 
@@ -167,3 +169,253 @@ func TestValidateGraphProperties(t *testing.T) {
 		}
 	}
 }
+
+//
+// growing a graph one vertex at a time, and removing edges (including
+// self-loops and parallel edges) must keep V() and E() in sync with
+// the adjacency lists
+//
+func TestAddVertexRemoveEdge(t *testing.T) {
+	g := New(0)
+	for i := int32(0); i < 3; i++ {
+		if v := g.AddVertex(); v != i {
+			t.Logf("expected new vertex: %d, got: %d\n", i, v)
+			t.Fail()
+		}
+	}
+
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 2)
+
+	if !g.RemoveEdge(1, 0) || !g.RemoveEdge(2, 2) || g.RemoveEdge(2, 2) || g.RemoveEdge(0, 2) {
+		t.Logf("unexpected edge removal result\n%s\n", g)
+		t.Fail()
+	}
+
+	want := create_test_graph(&graph_definition{3, 2, [][2]int32{{0, 1}, {1, 2}}})
+	if g.E() != 2 || !cmp_graph(g, want) {
+		t.Logf("expected:\n%s\ngot:\n%s\n", want, g)
+		t.Fail()
+	}
+
+	d := CreateDigraph(1)
+	d.AddEdge(d.AddVertex(), 0)
+	d.AddEdge(0, 1)
+
+	if !d.RemoveEdge(1, 0) || d.RemoveEdge(1, 0) || d.E() != 1 || len(d.Adj(1)) != 0 {
+		t.Logf("unexpected digraph after edge removal\n%s\n", d)
+		t.Fail()
+	}
+}
+
+func TestRemoveVertexMultiplicity(t *testing.T) {
+	g := New(4)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 0)
+	g.AddEdge(1, 1)
+	g.AddEdge(1, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.Attributes().SetVertex(3, "name", StringAttr("three"))
+	g.Attributes().SetEdge(3, 2, "name", StringAttr("two-three"))
+
+	if g.Multiplicity(0, 1) != 2 || g.Multiplicity(1, 1) != 2 || !g.HasEdge(3, 2) || g.HasEdge(0, 3) {
+		t.Logf("unexpected multiplicities\n%s\n", g)
+		t.Fail()
+	}
+
+	if n := g.RemoveEdges(1, 1); n != 2 || g.E() != 4 || g.HasEdge(1, 1) {
+		t.Logf("removed: %d self-loops, leaving:\n%s\n", n, g)
+		t.Fail()
+	}
+
+	// vertex 1 takes its edges along, 2, 3 are renumbered to 1, 2
+	if n, err := g.RemoveVertex(1); err != nil || n != 3 || g.V() != 3 || g.E() != 1 {
+		t.Logf("removed: %d edges, error: %v, leaving:\n%s\n", n, err, g)
+		t.FailNow()
+	}
+
+	want := create_test_graph(&graph_definition{3, 1, [][2]int32{{1, 2}}})
+	if !cmp_graph(g, want) {
+		t.Logf("expected:\n%s\ngot:\n%s\n", want, g)
+		t.Fail()
+	}
+
+	if val, _ := g.Attributes().Edge(1, 2, "name"); val.Str != "two-three" {
+		t.Logf("edge attributes weren't renumbered along with the vertices\n")
+		t.Fail()
+	}
+	if val, _ := g.Attributes().Vertex(2, "name"); val.Str != "three" {
+		t.Logf("vertex attributes weren't renumbered along with the vertices\n")
+		t.Fail()
+	}
+
+	d := CreateDigraph(3)
+	d.AddEdge(0, 1)
+	d.AddEdge(0, 1)
+	d.AddEdge(1, 2)
+	d.AddEdge(2, 0)
+	d.AddEdge(2, 2)
+
+	if d.Multiplicity(0, 1) != 2 || d.Multiplicity(1, 0) != 0 || d.Multiplicity(2, 2) != 1 {
+		t.Logf("unexpected multiplicities\n%s\n", d)
+		t.Fail()
+	}
+
+	if n, err := d.ClearVertex(2); err != nil || n != 3 || d.E() != 2 || len(d.Adj(2)) != 0 || d.V() != 3 {
+		t.Logf("cleared: %d edges, error: %v, leaving:\n%s\n", n, err, d)
+		t.Fail()
+	}
+
+	if n, err := d.RemoveVertex(0); err != nil || n != 2 || d.E() != 0 || d.V() != 2 {
+		t.Logf("removed: %d edges, error: %v, leaving:\n%s\n", n, err, d)
+		t.Fail()
+	}
+}
+
+//
+// vertices that are negative, or >= V have no edges to look up or
+// remove, and clearing or removing them is an error that leaves the
+// graph alone
+//
+func TestOutOfRangeVertices(t *testing.T) {
+	g := New(3)
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 2)
+
+	d := CreateDigraph(3)
+	d.AddEdge(0, 1)
+	d.AddEdge(2, 2)
+
+	for _, G := range []interface {
+		GraphOps
+		HasEdge(v, w int32) bool
+		Multiplicity(v, w int32) int32
+		RemoveEdge(v, w int32) bool
+		RemoveEdges(v, w int32) int32
+		ClearVertex(v int32) (int32, error)
+		RemoveVertex(v int32) (int32, error)
+	}{g, d} {
+		for _, v := range []int32{-1, 3, math.MaxInt32, math.MinInt32} {
+			if G.HasEdge(v, 0) || G.HasEdge(0, v) || G.Multiplicity(v, 1) != 0 || G.Multiplicity(1, v) != 0 {
+				t.Logf("vertex: %d, unexpected edges\n", v)
+				t.Fail()
+			}
+
+			if G.RemoveEdge(v, 0) || G.RemoveEdge(0, v) || G.RemoveEdges(v, 1) != 0 || G.RemoveEdges(1, v) != 0 {
+				t.Logf("vertex: %d, unexpected edge removal\n", v)
+				t.Fail()
+			}
+
+			if _, err := G.ClearVertex(v); !errors.Is(err, ErrBadVertex) {
+				t.Logf("vertex: %d, clear: expected ErrBadVertex, got: %v\n", v, err)
+				t.Fail()
+			}
+
+			if _, err := G.RemoveVertex(v); !errors.Is(err, ErrBadVertex) {
+				t.Logf("vertex: %d, remove: expected ErrBadVertex, got: %v\n", v, err)
+				t.Fail()
+			}
+		}
+
+		if G.V() != 3 || G.E() != 2 || !G.HasEdge(0, 1) || !G.HasEdge(2, 2) {
+			t.Logf("graph changed by out of range vertices:\n%s\n", G)
+			t.Fail()
+		}
+	}
+}
+
+func TestEdgePolicy(t *testing.T) {
+	g := New(3)
+	g.SetEdgePolicy(SimpleGraph)
+
+	test_data := []struct {
+		v, w int32
+		err  error
+	}{
+		{0, 1, nil},
+		{1, 0, ErrParallelEdge},
+		{0, 1, ErrParallelEdge},
+		{2, 2, ErrSelfLoop},
+		{1, 2, nil},
+	}
+
+	for _, td := range test_data {
+		if err := g.AddEdge(td.v, td.w); err != td.err {
+			t.Logf("edge: %d-%d, expected: %v, got: %v\n", td.v, td.w, td.err, err)
+			t.Fail()
+		}
+	}
+
+	if g.E() != 2 {
+		t.Logf("rejected edges were added:\n%s\n", g)
+		t.Fail()
+	}
+
+	// a digraph may have both v -> w, and w -> v
+	d := CreateDigraph(2)
+	d.SetEdgePolicy(RejectParallelEdges)
+
+	if d.AddEdge(0, 1) != nil || d.AddEdge(1, 0) != nil || d.AddEdge(1, 1) != nil || d.AddEdge(0, 1) != ErrParallelEdge {
+		t.Logf("unexpected digraph edge policy result:\n%s\n", d)
+		t.Fail()
+	}
+}
+
+func TestAutoGrow(t *testing.T) {
+	g := New(0)
+	g.SetAutoGrow(true)
+
+	for _, e := range [][2]int32{{0, 5}, {4, 3}, {0, 1}, {9, 12}, {6, 4}} {
+		if err := g.AddEdge(e[0], e[1]); err != nil {
+			t.Logf("edge: %v, unexpected error: %s\n", e, err)
+			t.Fail()
+		}
+	}
+
+	if g.V() != 13 || g.E() != 5 || len(g.Adj(12)) != 1 || len(g.Adj(11)) != 0 {
+		t.Logf("unexpected graph after growing:\n%s\n", g)
+		t.Fail()
+	}
+
+	if v := g.AddVertex(); v != 13 {
+		t.Logf("expected new vertex: 13, got: %d\n", v)
+		t.Fail()
+	}
+
+	if err := g.AddEdge(-1, 2); !errors.Is(err, ErrBadVertex) || g.V() != 14 {
+		t.Logf("expected a bad vertex error, got: %v\n", err)
+		t.Fail()
+	}
+
+	// rejected edges don't grow the graph
+	d := CreateDigraph(1)
+	d.SetAutoGrow(true)
+	d.SetEdgePolicy(SimpleGraph)
+
+	if err := d.AddEdge(3, 3); err != ErrSelfLoop || d.V() != 1 || d.E() != 0 {
+		t.Logf("unexpected digraph after a rejected edge, error: %v\n%s\n", err, d)
+		t.Fail()
+	}
+
+	// out of range vertices have no parallel edges
+	if d.AddEdge(4, 0) != nil || d.V() != 5 || !d.HasEdge(4, 0) {
+		t.Logf("unexpected digraph after growing:\n%s\n", d)
+		t.Fail()
+	}
+
+	// without auto-grow, out of range vertices are an error
+	d.SetAutoGrow(false)
+	if err := d.AddEdge(0, 5); !errors.Is(err, ErrBadVertex) || d.V() != 5 || d.E() != 1 {
+		t.Logf("expected a bad vertex error, got: %v\n", err)
+		t.Fail()
+	}
+
+	g.SetAutoGrow(false)
+	if err := g.AddEdge(14, 0); !errors.Is(err, ErrBadVertex) || g.V() != 14 {
+		t.Logf("expected a bad vertex error, got: %v\n", err)
+		t.Fail()
+	}
+}
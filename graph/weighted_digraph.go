@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of edge-weighted
+// digraphs.
+//
+package graph
+
+//
+// adjacency list representation of an edge-weighted digraph, which
+// contains 'V' vertices and 'E' edges. weights[v] is kept aligned
+// with adj[v]
+//
+type WeightedDigraph struct {
+	v       int32
+	e       int32
+	adj     []vertex_list_t
+	weights []weight_list_t
+}
+
+//
+// this function is called to create a new skeleton edge-weighted
+// digraph, with a specific number of vertices
+//
+func CreateWeightedDigraph(V int32) *WeightedDigraph {
+	return &WeightedDigraph{
+		v:       V,
+		e:       0,
+		adj:     make([]vertex_list_t, V),
+		weights: make([]weight_list_t, V),
+	}
+}
+
+func (G *WeightedDigraph) V() int32                  { return G.v }
+func (G *WeightedDigraph) E() int32                  { return G.e }
+func (G *WeightedDigraph) Adj(v int32) []int32       { return G.adj[v] }
+func (G *WeightedDigraph) Weights(v int32) []float64 { return G.weights[v] }
+
+//
+// in a digraph G, add an edge v -> w with a given weight
+//
+func (G *WeightedDigraph) AddEdge(v, w int32, weight float64) {
+	G.adj[v] = append(G.adj[v], w)
+	G.weights[v] = append(G.weights[v], weight)
+
+	G.e += 1
+	return
+}
+
+//
+// return the reverse of an edge-weighted digraph, each edge retains
+// its weight
+//
+func (G *WeightedDigraph) Reverse() (RevG *WeightedDigraph) {
+	RevG = CreateWeightedDigraph(G.V())
+	for v := int32(0); v < G.V(); v++ {
+		weights := G.Weights(v)
+		for i, w := range G.Adj(v) {
+			RevG.AddEdge(w, v, weights[i])
+		}
+	}
+
+	return
+}
+
+// pretty print an edge-weighted digraph structure.
+func (G *WeightedDigraph) String() string { return weighted_graph_stringifier(G) }
+
+//
+// enumerate some fundamental properties of a graph
+//
+func (G *WeightedDigraph) Degree(v int32) int32   { return int32(len(G.Adj(v))) }
+func (G *WeightedDigraph) AverageDegree() float64 { return average_degree(G) }
+func (G *WeightedDigraph) MaxDegree() int32       { return maximum_degree(G) }
@@ -0,0 +1,117 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// provides adjacency list based implementation of undirected
+// edge-weighted graphs
+//
+package graph
+
+import (
+	"fmt"
+)
+
+// weights of edges out of a vertex
+type weight_list_t []float64
+
+//
+// adjacency list representation of an edge-weighted graph, which
+// contains 'V' vertices and 'E' edges. weights[v] is kept aligned
+// with adj[v], so that plain GraphOps consumers never pay for the
+// weights
+//
+type WeightedGraph struct {
+	v       int32
+	e       int32
+	adj     []vertex_list_t
+	weights []weight_list_t
+}
+
+//
+// this function is called to create a new skeleton edge-weighted
+// graph, with a specific number of vertices
+//
+func NewWeighted(V int32) *WeightedGraph {
+	return &WeightedGraph{
+		v:       V,
+		e:       0,
+		adj:     make([]vertex_list_t, V),
+		weights: make([]weight_list_t, V),
+	}
+}
+
+func (G *WeightedGraph) V() int32                  { return G.v }
+func (G *WeightedGraph) E() int32                  { return G.e }
+func (G *WeightedGraph) Adj(v int32) []int32       { return G.adj[v] }
+func (G *WeightedGraph) Weights(v int32) []float64 { return G.weights[v] }
+
+//
+// in a graph G, add an edge between vertices 'v' and 'w' with a given
+// weight. for undirected graphs, this operation adds v-w, and w-v
+// edges as well
+//
+func (G *WeightedGraph) AddEdge(v, w int32, weight float64) {
+	G.adj[v] = append(G.adj[v], w)
+	G.weights[v] = append(G.weights[v], weight)
+
+	G.adj[w] = append(G.adj[w], v)
+	G.weights[w] = append(G.weights[w], weight)
+
+	G.e += 1
+	return
+}
+
+func (G *WeightedGraph) String() string { return weighted_graph_stringifier(G) }
+
+//
+// enumerate some fundamental properties of a graph
+//
+func (G *WeightedGraph) Degree(v int32) int32   { return int32(len(G.Adj(v))) }
+func (G *WeightedGraph) AverageDegree() float64 { return average_degree(G) }
+func (G *WeightedGraph) MaxDegree() int32       { return maximum_degree(G) }
+
+//
+// dump pretty-printing-string representation of an edge-weighted
+// graph. output format is as following
+//
+//     <line-001> V vertices, E edges
+//     <line-002> vertex-1 : adj-list-of(vertex-1) as w(weight)
+//     <line-003> vertex-2 : adj-list-of(vertex-2) as w(weight)
+//
+func weighted_graph_stringifier(G WeightedGraphOps) string {
+	str := fmt.Sprintf("%d vertices, %d edges\n", G.V(), G.E())
+
+	for v := int32(0); v < G.V(); v++ {
+		weights := G.Weights(v)
+		for i, w := range G.Adj(v) {
+			if i > 0 {
+				str += " "
+			}
+			str += fmt.Sprintf("%d(%g)", w, weights[i])
+		}
+		str += fmt.Sprintf("\n")
+	}
+
+	return str
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleWeightedGraph() {
+	g := NewWeighted(3)
+	g.AddEdge(0, 1, 0.5)
+	g.AddEdge(0, 2, 1.25)
+	g.AddEdge(1, 2, 2)
+
+	fmt.Print(g)
+
+	// Output:
+	// 3 vertices, 3 edges
+	// 1(0.5) 2(1.25)
+	// 0(0.5) 2(2)
+	// 0(1.25) 1(2)
+}
+
+//
+// weights of a vertex must stay aligned with its adjacency list, and
+// survive a reversal of the digraph
+//
+func TestWeightedDigraphReverse(t *testing.T) {
+	g := CreateWeightedDigraph(3)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(0, 2, 2)
+	g.AddEdge(2, 1, 3)
+
+	rev := g.Reverse()
+	if rev.E() != g.E() {
+		t.Logf("failed: edge count, want: %d, got: %d\n", g.E(), rev.E())
+		t.Fail()
+	}
+
+	for v := int32(0); v < g.V(); v++ {
+		weights := g.Weights(v)
+		for i, w := range g.Adj(v) {
+			found := false
+
+			rev_weights := rev.Weights(w)
+			for j, x := range rev.Adj(w) {
+				if x == v && rev_weights[j] == weights[i] {
+					found = true
+				}
+			}
+
+			if !found {
+				t.Logf("failed: edge %d->%d (%g) missing in reverse\n", v, w, weights[i])
+				t.Fail()
+			}
+		}
+	}
+}
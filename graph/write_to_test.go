@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+//
+// WriteTo(...) must report exactly what it wrote, and the output must
+// be loadable back into an identical graph
+//
+func TestGraphWriteTo(t *testing.T) {
+	for i, graph := range graphs {
+		g1 := create_test_graph(&graph.graph_defn)
+
+		var buf bytes.Buffer
+		n, err := g1.WriteTo(&buf)
+		if err != nil {
+			t.Logf("test-graph: %d, unexpected error: %s\n", i, err)
+			t.FailNow()
+		}
+
+		if n != int64(buf.Len()) || buf.String() != g1.Serialize() {
+			t.Logf("test-graph: %d, byte-count: %d, expected: %d\n", i, n, buf.Len())
+			t.Fail()
+		}
+
+		g2, err := LoadFromReader(bufio.NewReader(&buf))
+		if err != nil || !cmp_graph(g1, g2) {
+			t.Logf("test-graph: %d, round-trip failed, error: %v\n", i, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestDigraphWriteTo(t *testing.T) {
+	d1 := create_xml_test_digraph()
+
+	var buf bytes.Buffer
+	n, err := d1.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Logf("unexpected error: %v, byte-count: %d, expected: %d\n", err, n, buf.Len())
+		t.FailNow()
+	}
+
+	d2, err := LoadDigraphFromReader(bufio.NewReader(&buf))
+	if err != nil || !cmp_graph(d1, d2) {
+		t.Logf("round-trip failed, error: %v\n", err)
+		t.Fail()
+	}
+}
+
+//
+// String() output must not change now that it is built on top of
+// DumpTo(...)
+//
+func TestGraphDumpTo(t *testing.T) {
+	for i, graph := range graphs {
+		g := create_test_graph(&graph.graph_defn)
+
+		var sb strings.Builder
+		n, _ := g.DumpTo(&sb)
+
+		if sb.String() != concat_stringifier(g) || n != int64(sb.Len()) {
+			t.Logf("test-graph: %d, got:\n%s\nexpected:\n%s\n", i, sb.String(), concat_stringifier(g))
+			t.Fail()
+		}
+	}
+}
+
+//
+// the string-concatenating serializer which WriteTo(...) replaces,
+// kept around for comparison
+//
+func concat_serialize(G *Graph) string {
+	str := ""
+
+	str += fmt.Sprintf("%d\n", G.V())
+	str += fmt.Sprintf("%d\n", G.E())
+
+	for v := int32(0); v < G.V(); v++ {
+		for _, w := range G.Adj(v) {
+			if v > w {
+				continue
+			}
+			str += fmt.Sprintf("%d %d\n", v, w)
+		}
+	}
+
+	return str
+}
+
+func concat_stringifier(G GraphOps) string {
+	str := fmt.Sprintf("%d vertices, %d edges\n", G.V(), G.E())
+
+	for v := int32(0); v < G.V(); v++ {
+		for _, w := range G.Adj(v) {
+			str += fmt.Sprintf("%d ", w)
+		}
+		str += fmt.Sprintf("\n")
+	}
+
+	return str
+}
+
+// a ring of 'V' vertices with a chord from each vertex to its opposite
+func create_bench_graph(V int32) *Graph {
+	g := New(V)
+	for v := int32(0); v < V; v++ {
+		g.AddEdge(v, (v+1)%V)
+		if v < V/2 {
+			g.AddEdge(v, v+V/2)
+		}
+	}
+
+	return g
+}
+
+func BenchmarkSerializeConcat(b *testing.B) {
+	g := create_bench_graph(4096)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		concat_serialize(g)
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	g := create_bench_graph(4096)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.WriteTo(ioutil.Discard)
+	}
+}
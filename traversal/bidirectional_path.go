@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// in this package we try to separate out graph traversal-order from
+// actual procedures which use these traversals.
+//
+// this file implements the point-to-point shortest path using a
+// bidirectional breadth-first-search i.e. searching from both the
+// source and the destination till the two searches meet
+//
+package traversal
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+)
+
+//
+// result of a bidirectional search from source -> dest
+//
+type BidirectionalPath struct {
+	path    []int32
+	visited int32
+}
+
+//
+// this function returns the shortest path from source -> dest in 'G'
+// by running a breadth-first-search forward from the source on 'G',
+// and backward from the destination on 'RevG', which must be the
+// reverse of 'G'.
+//
+// at each step, a complete level of the smaller frontier is expanded,
+// and the search stops once the two searches meet. an error is
+// signalled if no path exists.
+//
+func BidirectionalShortestPath(G, RevG graph.GraphOps, source, dest int32) (bp *BidirectionalPath, err error) {
+	if source < 0 || source >= G.V() || dest < 0 || dest >= G.V() {
+		err = fmt.Errorf("bogus vertex: %d or %d\n", source, dest)
+		return
+	}
+
+	fwd := new_bfs_side(G, source)
+	bwd := new_bfs_side(RevG, dest)
+	meet := int32(-1)
+
+	if source == dest {
+		meet = source
+	}
+
+	for meet < 0 && len(fwd.frontier) > 0 && len(bwd.frontier) > 0 {
+		if len(fwd.frontier) <= len(bwd.frontier) {
+			meet = fwd.expand_level(bwd)
+		} else {
+			meet = bwd.expand_level(fwd)
+		}
+	}
+
+	bp = &BidirectionalPath{
+		visited: fwd.visited + bwd.visited,
+	}
+
+	if meet < 0 {
+		err = fmt.Errorf("no path from: %d to: %d\n", source, dest)
+		return
+	}
+
+	// source -> meet, and then meet -> dest
+	for v := meet; v != source; v = fwd.edge_to[v] {
+		bp.path = append(bp.path, v)
+	}
+	bp.path = append(bp.path, source)
+
+	for m, n := 0, len(bp.path)-1; m < n; m, n = m+1, n-1 {
+		bp.path[m], bp.path[n] = bp.path[n], bp.path[m]
+	}
+
+	for v := meet; v != dest; {
+		v = bwd.edge_to[v]
+		bp.path = append(bp.path, v)
+	}
+
+	return
+}
+
+//
+// this is a convenience interface over
+// BidirectionalShortestPath(...) for undirected graphs, which are
+// their own reverse
+//
+func GraphShortestPath(g *graph.Graph, source, dest int32) (*BidirectionalPath, error) {
+	return BidirectionalShortestPath(g, g, source, dest)
+}
+
+//
+// this is a convenience interface over
+// BidirectionalShortestPath(...) for digraphs. note that the digraph
+// is reversed on each invokation, clients doing repeated queries
+// should reverse it just once, and call
+// BidirectionalShortestPath(...) directly.
+//
+func DigraphShortestPath(g *graph.Digraph, source, dest int32) (*BidirectionalPath, error) {
+	return BidirectionalShortestPath(g, g.Reverse(), source, dest)
+}
+
+//
+// the path source -> dest (if one exists), and total number of
+// vertices visited by both searches
+//
+func (bp *BidirectionalPath) Path() []int32  { return bp.path }
+func (bp *BidirectionalPath) Visited() int32 { return bp.visited }
+
+//
+// private unexported stuff
+//
+
+// one half of a bidirectional search
+type bfs_side_t struct {
+	G        graph.GraphOps
+	marked   []bool
+	edge_to  []int32
+	frontier []int32
+	visited  int32
+}
+
+// start a search on 'G' from 'source'
+func new_bfs_side(G graph.GraphOps, source int32) (side *bfs_side_t) {
+	side = &bfs_side_t{
+		G:        G,
+		marked:   make([]bool, G.V()),
+		edge_to:  make([]int32, G.V()),
+		frontier: []int32{source},
+		visited:  1,
+	}
+	side.marked[source] = true
+
+	return
+}
+
+//
+// expand the current frontier by one level. returns the vertex where
+// this side met the 'other' side, or -1 if they haven't met yet.
+//
+// all vertices in a frontier are equidistant from the origin, so any
+// meeting vertex found in this level gives a shortest path
+//
+func (side *bfs_side_t) expand_level(other *bfs_side_t) (meet int32) {
+	var next []int32
+
+	meet = -1
+	for _, v := range side.frontier {
+		for _, w := range side.G.Adj(v) {
+			if side.marked[w] {
+				continue
+			}
+
+			side.marked[w] = true
+			side.edge_to[w] = v
+			side.visited += 1
+			next = append(next, w)
+
+			if other.marked[w] {
+				meet = w
+				return
+			}
+		}
+	}
+	side.frontier = next
+
+	return
+}
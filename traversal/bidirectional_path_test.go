@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2014, Anupam Kapoor. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT OWNER OR CONTRIBUTORS BE LIABLE FOR
+// ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+// (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+// LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+// ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+// SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+// Author: anupam.kapoor@gmail.com (Anupam Kapoor)
+//
+// in this package we try to separate out graph traversal-order from
+// actual procedures which use these traversals.
+//
+// this file implements test routines for bidirectional shortest paths
+//
+package traversal
+
+import (
+	"fmt"
+	"github.com/anupamk/common-utilz/graph"
+	"testing"
+)
+
+// create a rows x cols grid graph
+func create_grid_graph(rows, cols int32) *graph.Graph {
+	g := graph.New(rows * cols)
+
+	for r := int32(0); r < rows; r++ {
+		for c := int32(0); c < cols; c++ {
+			v := r*cols + c
+			if c+1 < cols {
+				g.AddEdge(v, v+1)
+			}
+			if r+1 < rows {
+				g.AddEdge(v, v+cols)
+			}
+		}
+	}
+
+	return g
+}
+
+func ExampleDigraphShortestPath() {
+	g := graph.CreateDigraph(6)
+	for _, e := range [][2]int32{{0, 1}, {1, 2}, {2, 3}, {0, 4}, {4, 3}, {3, 5}} {
+		g.AddEdge(e[0], e[1])
+	}
+
+	bp, _ := DigraphShortestPath(g, 0, 5)
+	fmt.Println(bp.Path())
+
+	_, err := DigraphShortestPath(g, 5, 0)
+	fmt.Print(err)
+
+	// Output:
+	// [0 4 3 5]
+	// no path from: 5 to: 0
+}
+
+//
+// bidirectional search must find paths just as short as the single
+// source shortest paths do. for nearby destinations, it must visit
+// only a fraction of the graph.
+//
+func TestBidirectionalMatchesSingleSource(t *testing.T) {
+	g := create_grid_graph(24, 24)
+	source := int32(0)
+
+	ssp, _ := SingleSourceShortestPaths(g, source)
+	for dest := int32(0); dest < g.V(); dest++ {
+		bp, err := GraphShortestPath(g, source, dest)
+		if err != nil {
+			t.Logf("failed: %d->%d, unexpected error: %s\n", source, dest, err)
+			t.Fail()
+			continue
+		}
+
+		want := ssp.PathTo(dest)
+		got := bp.Path()
+		if len(got) != len(want) || got[0] != source || got[len(got)-1] != dest {
+			t.Logf("failed: %d->%d, got: %v, want: %v\n", source, dest, got, want)
+			t.Fail()
+			continue
+		}
+
+		// consecutive vertices must be adjacent
+		for i := 1; i < len(got); i++ {
+			adjacent := false
+			for _, w := range g.Adj(got[i-1]) {
+				adjacent = adjacent || (w == got[i])
+			}
+
+			if !adjacent {
+				t.Logf("failed: %d->%d, bogus path: %v\n", source, dest, got)
+				t.Fail()
+				break
+			}
+		}
+	}
+
+	bp, _ := GraphShortestPath(g, source, 3*24+3)
+	if bp.Visited() >= g.V()/4 {
+		t.Logf("failed: visited: %d, vertices: %d\n", bp.Visited(), g.V())
+		t.Fail()
+	}
+}